@@ -3,6 +3,14 @@ package main
 type CLI struct {
 	Verbose bool `short:"v" long:"verbose" description:"Show verbose debug information"`
 
-	Run     CLIRun     `cmd:"run" help:"Run a k6 task"`
-	Version CLIVersion `cmd:"version" help:"Show the k6ctl version"`
+	Run      CLIRun      `cmd:"run" help:"Run a k6 task"`
+	Validate CLIValidate `cmd:"validate" help:"Validate a k6 task config"`
+	Migrate  CLIMigrate  `cmd:"migrate" help:"Rewrite a k6 task config to the latest version"`
+	Schema   CLISchema   `cmd:"schema" help:"Print the JSON Schema of the k6 task config"`
+	Render   CLIRender   `cmd:"render" help:"Print the objects of a k6 task run as multi-document YAML"`
+	Trigger  CLITrigger  `cmd:"trigger" help:"Start an ad-hoc run of a k6 task scheduled as CronJob"`
+	Delete   CLIDelete   `cmd:"delete" help:"Delete the objects created by a k6 task"`
+	Status   CLIStatus   `cmd:"status" help:"Show the status of a k6 task"`
+	Logs     CLILogs     `cmd:"logs" help:"Show the logs of a k6 task"`
+	Version  CLIVersion  `cmd:"version" help:"Show the k6ctl version"`
 }
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/Azure/k6ctl/internal/task"
+)
+
+type CLIDelete struct {
+	TargetFlags       `embed:""`
+	TaskSelectorFlags `embed:""`
+
+	Wait        bool          `default:"false" long:"wait" help:"Wait until all objects of the task are removed"`
+	WaitTimeout time.Duration `default:"0" long:"wait-timeout" help:"Maximum duration to wait for the removal. 0 means no timeout"`
+
+	Output io.Writer `kong:"-"`
+}
+
+func (c *CLIDelete) BeforeApply() error {
+	if c.Output == nil {
+		c.Output = os.Stderr
+	}
+
+	return nil
+}
+
+func (c *CLIDelete) Run() error {
+	taskRef, err := c.resolveTask()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	deleted, err := task.DeleteTask(
+		ctx,
+		c.target(),
+		taskRef,
+		task.WithWait(c.Wait, c.WaitTimeout),
+	)
+	for _, obj := range deleted {
+		if _, printErr := fmt.Fprintf(c.Output, "Deleted %s %s/%s\n", obj.Kind, obj.Namespace, obj.Name); printErr != nil {
+			return printErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(deleted) == 0 {
+		_, err = fmt.Fprintf(c.Output, "No objects found for task %s\n", taskRef)
+	}
+
+	return err
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Azure/k6ctl/internal/config"
+	coreconfig "github.com/Azure/k6ctl/internal/config/core"
+	"github.com/Azure/k6ctl/internal/target"
+	"github.com/Azure/k6ctl/internal/task"
+)
+
+const defaultTaskConfigFile = "k6ctl.yaml"
+
+// TargetFlags defines the flags for selecting the target cluster.
+type TargetFlags struct {
+	Kubeconfig string `required:"" type:"existingfile" env:"KUBECONFIG" long:"kubeconfig" help:"Path to the kubeconfig file to use for CLI requests"`
+}
+
+func (f *TargetFlags) target() target.Target {
+	return &target.StaticTarget{
+		Kubeconfig: f.Kubeconfig,
+	}
+}
+
+// OptionalTargetFlags defines the flags for selecting the target cluster, for the commands which
+// can render the objects without accessing the cluster.
+type OptionalTargetFlags struct {
+	Kubeconfig string `type:"existingfile" env:"KUBECONFIG" long:"kubeconfig" help:"Path to the kubeconfig file to use for CLI requests, not required for rendering the objects with redacted configs"`
+}
+
+func (f *OptionalTargetFlags) target() target.Target {
+	return &target.StaticTarget{
+		Kubeconfig: f.Kubeconfig,
+	}
+}
+
+// TaskConfigFlags defines the flags for locating the task config.
+type TaskConfigFlags struct {
+	TaskConfig string `type:"existingfile" short:"c" long:"config" help:"Path to the task config file to use for CLI requests"`
+	BaseDir    string `required:"" default:"." type:"existingdir" short:"d" long:"base-dir" help:"Base directory to use for relative paths"`
+}
+
+func (f *TaskConfigFlags) resolveBaseDir() (string, error) {
+	return filepath.Abs(filepath.Clean(f.BaseDir))
+}
+
+// resolveTaskConfigFile resolves the path of the task config file.
+func (f *TaskConfigFlags) resolveTaskConfigFile(baseDir string) (string, error) {
+	taskConfigFile := f.TaskConfig
+	if taskConfigFile == "" {
+		// defaults to k6ctl.yaml in the base directory
+		taskConfigFile = filepath.Join(baseDir, defaultTaskConfigFile)
+	}
+
+	stat, err := os.Stat(taskConfigFile)
+	if err != nil {
+		return "", fmt.Errorf("no task config file found at %q", taskConfigFile)
+	}
+	if stat.IsDir() {
+		return "", fmt.Errorf("task config file %q is a directory", taskConfigFile)
+	}
+
+	return taskConfigFile, nil
+}
+
+// resolveTaskConfig loads and validates the task config.
+func (f *TaskConfigFlags) resolveTaskConfig(baseDir string) (*task.Schema, error) {
+	taskConfigFile, err := f.resolveTaskConfigFile(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return task.ValidateSchemaFile(taskConfigFile, baseDir)
+}
+
+// loadTaskConfig loads the task config without validating its semantics.
+func (f *TaskConfigFlags) loadTaskConfig(baseDir string) (*task.Schema, error) {
+	taskConfigFile, err := f.resolveTaskConfigFile(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return task.LoadSchemaFromFile(taskConfigFile)
+}
+
+// TaskNameFlags defines the flags for naming a task created in the cluster.
+// The task can be named by name and namespace, or from the task config.
+type TaskNameFlags struct {
+	TaskConfigFlags `embed:""`
+
+	Name      string `arg:"" optional:"" help:"Name of the task. Defaults to the name from the task config"`
+	Namespace string `short:"n" long:"namespace" help:"Namespace of the task. Defaults to the namespace from the task config"`
+}
+
+// resolveTask resolves the reference to the named task.
+func (f *TaskNameFlags) resolveTask() (task.TaskRef, error) {
+	rv := task.TaskRef{
+		Namespace: f.Namespace,
+		Name:      f.Name,
+	}
+	if rv.Name != "" && rv.Namespace != "" {
+		return rv, nil
+	}
+
+	baseDir, err := f.resolveBaseDir()
+	if err != nil {
+		return task.TaskRef{}, err
+	}
+	taskConfig, err := f.loadTaskConfig(baseDir)
+	if err != nil {
+		if rv.Name != "" {
+			return task.TaskRef{}, fmt.Errorf("--namespace is required when no task config is available: %w", err)
+		}
+		return task.TaskRef{}, err
+	}
+
+	if rv.Name == "" {
+		rv.Name = taskConfig.Name
+	}
+	if rv.Namespace == "" {
+		rv.Namespace = taskConfig.K6.Namespace
+	}
+
+	return rv, nil
+}
+
+// TaskSelectorFlags defines the flags for selecting the runs of a task created in the cluster.
+type TaskSelectorFlags struct {
+	TaskNameFlags `embed:""`
+
+	RunID string `name:"run-id" help:"Run ID of the task. Defaults to all runs of the task"`
+}
+
+// resolveTask resolves the reference to the selected task runs.
+func (f *TaskSelectorFlags) resolveTask() (task.TaskRef, error) {
+	rv, err := f.TaskNameFlags.resolveTask()
+	if err != nil {
+		return task.TaskRef{}, err
+	}
+	rv.RunID = f.RunID
+
+	return rv, nil
+}
+
+// TaskRunFlags defines the flags for building a run of the task.
+type TaskRunFlags struct {
+	Script       string            `arg:"" default:"script.js" help:"Script to run, or a k6 archive (.tar) created by k6 archive"`
+	Parameters   map[string]string `short:"p" long:"parameter" help:"Parameters to pass to the script (can be used multiple times)"`
+	Instances    int32             `default:"1" long:"instances" help:"Number of instances to run"`
+	RunID        string            `name:"run-id" help:"ID of the run. Defaults to a generated ID"`
+	Distribution string            `enum:",replicate,segments" default:"" long:"distribution" help:"How the load is distributed across instances: replicate runs the full script in every instance, segments splits it with k6 execution segments. Defaults to k6.distribution from the task config"`
+
+	StartBarrier        bool          `name:"start-barrier" help:"Start all instances together once all of them are ready. Defaults to k6.startBarrier.enabled from the task config"`
+	StartBarrierTimeout time.Duration `name:"start-barrier-timeout" help:"How long to wait for all instances to be ready before aborting the run. Defaults to k6.startBarrier.timeout from the task config, or 5m"`
+
+	Summary     bool   `name:"summary" help:"Collect the end-of-test summaries of all instances, print the merged summary and save it to a local file. Defaults to k6.summary.enabled from the task config"`
+	SummaryFile string `name:"summary-file" help:"Local file to save the merged summary to. Implies --summary. Defaults to k6.summary.file from the task config, or k6ctl-summary-<name>-<run-id>.json"`
+
+	ShowDeps bool `name:"show-deps" help:"List the local files imported or opened by the script, which are mounted automatically"`
+
+	Archive  bool   `name:"archive" help:"Bundle the script with its local and remote imports into a k6 archive with the local k6 binary, and run the archive"`
+	K6Binary string `name:"k6-binary" default:"k6" help:"Local k6 binary to build the archive with"`
+
+	Vendor bool `name:"vendor" help:"Fetch the remote modules imported by the scripts on the client and mount them with the scripts. Defaults to vendor.enabled from the task config"`
+}
+
+// runTask loads the task config and runs the task with the flags and the extra options.
+// With redactConfigs, the configs are rendered as redacted placeholders without resolving them,
+// so no parameter is prompted for.
+func (f *TaskRunFlags) runTask(
+	ctx context.Context,
+	target target.Target,
+	taskConfigFlags *TaskConfigFlags,
+	redactConfigs bool,
+	extraOptions ...task.RunTaskOption,
+) error {
+	baseDir, err := taskConfigFlags.resolveBaseDir()
+	if err != nil {
+		return err
+	}
+
+	taskConfig, err := taskConfigFlags.resolveTaskConfig(baseDir)
+	if err != nil {
+		return err
+	}
+	if _, ok := target.GetKubeconfig(); !ok && !redactConfigs && coreconfig.RequiresKubeconfig(taskConfig.Configs) {
+		return fmt.Errorf("missing flags: --kubeconfig is required to resolve the configs from the cluster")
+	}
+
+	runOptions := []task.RunTaskOption{
+		task.WithInstances(f.Instances),
+	}
+	if f.RunID != "" {
+		runOptions = append(runOptions, task.WithRunID(f.RunID))
+	}
+	if f.Distribution != "" {
+		runOptions = append(runOptions, task.WithDistribution(f.Distribution))
+	}
+	if f.StartBarrier || f.StartBarrierTimeout > 0 {
+		runOptions = append(runOptions, task.WithStartBarrier(f.StartBarrier, f.StartBarrierTimeout))
+	}
+	if f.Summary || f.SummaryFile != "" {
+		runOptions = append(runOptions, task.WithSummary(true, f.SummaryFile))
+	}
+	if f.ShowDeps {
+		runOptions = append(runOptions, task.WithShowDependencies(os.Stderr))
+	}
+	if f.Vendor {
+		runOptions = append(runOptions, task.WithVendor(true))
+	}
+	if f.Archive {
+		runOptions = append(runOptions, task.WithArchive(f.K6Binary))
+	}
+	runOptions = append(runOptions, extraOptions...)
+
+	cpRegistry := config.NewRegistry()
+
+	registerOptions := []coreconfig.RegisterOption{
+		coreconfig.WithBaseDir(baseDir),
+	}
+	expectedRunDuration, err := task.ExpectedRunDuration(taskConfig.K6, runOptions...)
+	if err != nil {
+		return err
+	}
+	if expectedRunDuration > 0 {
+		registerOptions = append(registerOptions, coreconfig.WithExpectedRunDuration(expectedRunDuration))
+	}
+
+	configProviders := taskConfig.Configs
+	if redactConfigs {
+		// no parameter is resolved without configs
+		configProviders = nil
+	}
+	if err := coreconfig.RegisterProviders(
+		cpRegistry,
+		configProviders,
+		f.Parameters,
+		registerOptions...,
+	); err != nil {
+		return err
+	}
+
+	stopConfigPlugins, err := task.LoadConfigPlugins(
+		ctx,
+		cpRegistry,
+		taskConfig.K6,
+	)
+	if err != nil {
+		return err
+	}
+	defer stopConfigPlugins()
+
+	return task.RunTask(
+		ctx,
+		target,
+		cpRegistry.GetByName,
+		taskConfig,
+		baseDir,
+		f.Script,
+		runOptions...,
+	)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/Azure/k6ctl/internal/task"
+)
+
+type CLILogs struct {
+	TargetFlags       `embed:""`
+	TaskSelectorFlags `embed:""`
+
+	Follow bool          `default:"false" short:"f" long:"follow" help:"Keep streaming the logs until interrupted"`
+	Since  time.Duration `default:"0" long:"since" help:"Only show logs newer than the given duration (e.g. 5m). 0 means all logs"`
+	Tail   int64         `default:"-1" long:"tail" help:"Number of lines from the end of the logs to show. -1 means all lines"`
+	Pods   []string      `name:"pod" help:"Only show logs of the given pod (can be used multiple times)"`
+	Prefix bool          `default:"false" long:"prefix" help:"Always prefix each line with the pod name. By default, prefix is added when the task runs multiple pods"`
+
+	Output io.Writer `kong:"-"`
+}
+
+func (c *CLILogs) BeforeApply() error {
+	if c.Output == nil {
+		c.Output = os.Stdout
+	}
+
+	return nil
+}
+
+func (c *CLILogs) Run() error {
+	taskRef, err := c.resolveTask()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	return task.TaskLogs(
+		ctx,
+		c.target(),
+		taskRef,
+		task.WithLogsFollow(c.Follow),
+		task.WithLogsSince(c.Since),
+		task.WithLogsTail(c.Tail),
+		task.WithLogsPods(c.Pods...),
+		task.WithLogsPrefix(c.Prefix),
+		task.WithLogsOutput(c.Output),
+	)
+}
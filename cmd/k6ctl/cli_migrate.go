@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Azure/k6ctl/internal/task"
+)
+
+type CLIMigrate struct {
+	TaskConfigFlags `embed:""`
+
+	DryRun bool `name:"dry-run" help:"Print the migrated task config instead of rewriting the file"`
+
+	Output    io.Writer `kong:"-"`
+	ErrOutput io.Writer `kong:"-"`
+}
+
+func (c *CLIMigrate) BeforeApply() error {
+	c.Output = os.Stdout
+	c.ErrOutput = os.Stderr
+	return nil
+}
+
+func (c *CLIMigrate) Run() error {
+	baseDir, err := c.resolveBaseDir()
+	if err != nil {
+		return err
+	}
+	taskConfigFile, err := c.resolveTaskConfigFile(baseDir)
+	if err != nil {
+		return err
+	}
+
+	stat, err := os.Stat(taskConfigFile)
+	if err != nil {
+		return err
+	}
+	source, err := os.ReadFile(taskConfigFile)
+	if err != nil {
+		return err
+	}
+
+	migrated, from, err := task.MigrateSchema(taskConfigFile, source)
+	if err != nil {
+		return err
+	}
+	if c.DryRun {
+		_, err := c.Output.Write(migrated)
+		return err
+	}
+	if from == task.LatestSchemaVersion {
+		_, err := fmt.Fprintf(c.ErrOutput, "%s is already at version %s\n", taskConfigFile, task.LatestSchemaVersion)
+		return err
+	}
+
+	if err := os.WriteFile(taskConfigFile, migrated, stat.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write %q: %w", taskConfigFile, err)
+	}
+	_, err = fmt.Fprintf(
+		c.ErrOutput, "%s migrated from %s to %s\n",
+		taskConfigFile, task.DisplaySchemaVersion(from), task.LatestSchemaVersion,
+	)
+	return err
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/Azure/k6ctl/internal/task"
+)
+
+type CLIRender struct {
+	OptionalTargetFlags `embed:""`
+	TaskConfigFlags     `embed:""`
+	TaskRunFlags        `embed:""`
+
+	ShowSecrets bool `name:"show-secrets" help:"Show the secret values instead of redacting them"`
+
+	Output io.Writer `kong:"-"`
+}
+
+func (c *CLIRender) BeforeApply() error {
+	c.Output = os.Stdout
+	return nil
+}
+
+func (c *CLIRender) Run() error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	return c.runTask(ctx, c.target(), &c.TaskConfigFlags, !c.ShowSecrets, task.WithDryRun(c.Output, c.ShowSecrets))
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/Azure/k6ctl/internal/config"
+	coreconfig "github.com/Azure/k6ctl/internal/config/core"
+	"github.com/Azure/k6ctl/internal/task"
+)
+
+type CLISchema struct {
+	TaskConfigFlags `embed:""`
+
+	Plugins bool `name:"plugins" help:"Include the params schemas of the config plugins declared in the task config"`
+
+	Output io.Writer `kong:"-"`
+}
+
+func (c *CLISchema) BeforeApply() error {
+	c.Output = os.Stdout
+	return nil
+}
+
+func (c *CLISchema) Run() error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	cpRegistry := config.NewRegistry()
+	// no parameter is resolved without configs
+	if err := coreconfig.RegisterProviders(cpRegistry, nil, nil); err != nil {
+		return err
+	}
+
+	if c.Plugins {
+		baseDir, err := c.resolveBaseDir()
+		if err != nil {
+			return err
+		}
+		taskConfig, err := c.loadTaskConfig(baseDir)
+		if err != nil {
+			return err
+		}
+
+		stopConfigPlugins, err := task.LoadConfigPlugins(ctx, cpRegistry, taskConfig.K6)
+		if err != nil {
+			return err
+		}
+		defer stopConfigPlugins()
+	}
+
+	enc := json.NewEncoder(c.Output)
+	enc.SetIndent("", "  ")
+	return enc.Encode(task.JSONSchema(config.ParamsSchemas(cpRegistry)))
+}
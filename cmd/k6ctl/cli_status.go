@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Azure/k6ctl/internal/task"
+)
+
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+type CLIStatus struct {
+	TargetFlags       `embed:""`
+	TaskSelectorFlags `embed:""`
+
+	OutputFormat string `default:"text" enum:"text,json" short:"o" long:"output" help:"Output format (text, json)"`
+
+	Output io.Writer `kong:"-"`
+}
+
+func (c *CLIStatus) BeforeApply() error {
+	if c.Output == nil {
+		c.Output = os.Stdout
+	}
+
+	return nil
+}
+
+func (c *CLIStatus) Run() error {
+	taskRef, err := c.resolveTask()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	status, err := task.GetTaskStatus(ctx, c.target(), taskRef)
+	if err != nil {
+		return err
+	}
+
+	switch c.OutputFormat {
+	case outputFormatJSON:
+		enc := json.NewEncoder(c.Output)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
+	default:
+		return printTaskStatus(c.Output, status)
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format(time.RFC3339)
+}
+
+// errWriter remembers the first write error so consecutive prints can be checked once.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...any) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
+
+func printTaskStatus(out io.Writer, status *task.TaskStatus) error {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	w := &errWriter{w: tw}
+
+	for idx, job := range status.Jobs {
+		if idx > 0 {
+			w.printf("\n")
+		}
+
+		jobState := "Running"
+		if job.Finished {
+			jobState = "Finished"
+		}
+		w.printf("Job:\t%s/%s (%s)\n", status.Namespace, job.Name, jobState)
+		w.printf("Started:\t%s\n", formatTime(job.StartTime))
+		w.printf("Completed:\t%s\n", formatTime(job.CompletionTime))
+		w.printf(
+			"Pods:\t%d active / %d succeeded / %d failed (%d completions)\n\n",
+			job.Active, job.Succeeded, job.Failed, job.Completions,
+		)
+
+		w.printf("POD\tPHASE\tNODE\tSTARTED\tEXIT CODE\tREASON\n")
+		for _, pod := range job.Pods {
+			exitCode := "-"
+			if pod.ExitCode != nil {
+				exitCode = strconv.Itoa(int(*pod.ExitCode))
+			}
+			w.printf(
+				"%s\t%s\t%s\t%s\t%s\t%s\n",
+				pod.Name, pod.Phase, valueOrDash(pod.Node), formatTime(pod.StartTime), exitCode, valueOrDash(pod.Reason),
+			)
+		}
+	}
+	if w.err != nil {
+		return w.err
+	}
+
+	return tw.Flush()
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/Azure/k6ctl/internal/task"
+)
+
+type CLITrigger struct {
+	TargetFlags   `embed:""`
+	TaskNameFlags `embed:""`
+
+	NoFollowLogs bool   `default:"false" long:"no-follow-logs" help:"Do not follow logs"`
+	NoWait       bool   `default:"false" long:"no-wait" help:"Do not wait for the task to finish and check its result"`
+	RunID        string `name:"run-id" help:"ID of the run. Defaults to a generated ID"`
+}
+
+func (c *CLITrigger) Run() error {
+	taskRef, err := c.resolveTask()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	runOptions := []task.RunTaskOption{
+		task.WithFollowLogs(!c.NoFollowLogs),
+		task.WithWaitForCompletion(!c.NoWait),
+	}
+	if c.RunID != "" {
+		runOptions = append(runOptions, task.WithRunID(c.RunID))
+	}
+
+	return task.TriggerTask(ctx, c.target(), taskRef, runOptions...)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+type CLIValidate struct {
+	TaskConfigFlags `embed:""`
+
+	Output io.Writer `kong:"-"`
+}
+
+func (c *CLIValidate) BeforeApply() error {
+	c.Output = os.Stderr
+	return nil
+}
+
+func (c *CLIValidate) Run() error {
+	baseDir, err := c.resolveBaseDir()
+	if err != nil {
+		return err
+	}
+
+	taskConfigFile, err := c.resolveTaskConfigFile(baseDir)
+	if err != nil {
+		return err
+	}
+	if _, err := c.resolveTaskConfig(baseDir); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.Output, "%s is valid\n", taskConfigFile)
+	return err
+}
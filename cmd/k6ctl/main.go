@@ -1,12 +1,25 @@
 package main
 
 import (
+	"errors"
+
 	"github.com/alecthomas/kong"
 )
 
+// exitCoder is implemented by errors carrying a specific process exit code.
+type exitCoder interface {
+	ExitCode() int
+}
+
 func main() {
 	cli := &CLI{}
 	cliCtx := kong.Parse(cli)
 	err := cliCtx.Run()
+
+	var withExitCode exitCoder
+	if err != nil && errors.As(err, &withExitCode) {
+		cliCtx.Errorf("%s", err)
+		cliCtx.Exit(withExitCode.ExitCode())
+	}
 	cliCtx.FatalIfErrorf(err)
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Azure/k6ctl/internal/config"
 	configplugin "github.com/Azure/k6ctl/internal/config/plugin"
+	"github.com/Azure/k6ctl/internal/jsonschema"
 )
 
 // ConfigProvider - config provider.
@@ -13,6 +14,12 @@ type ConfigProvider = config.Provider
 // ConfigProviderRegistry - registry of config providers.
 type ConfigProviderRegistry = config.ProviderRegistry
 
+// ConfigProvideOption - option for creating a config provider.
+type ConfigProvideOption = config.ProvideOption
+
+// JSONSchema - JSON Schema describing the params of a config provider.
+type JSONSchema = jsonschema.Schema
+
 // NewConfigProviderRegistry creates a new config provider registry instance.
 var NewConfigProviderRegistry = config.NewRegistry
 
@@ -31,13 +38,24 @@ func ProvideConfig[T any](
 	// FIXME: generic alias
 	loader func(ctx context.Context, target Target, userInput map[string]any) (T, error),
 	resolver func(ctx context.Context, target Target, params T) (string, error),
+	opts ...ConfigProvideOption,
 ) ConfigProvider {
 	return config.Provide[T](
 		name,
 		loader,
 		resolver,
+		opts...,
 	)
 }
 
+// WithConfigParamsSchema specifies the JSON Schema of the config provider params.
+// The schema is published by `k6ctl schema` for validating the params in editors.
+var WithConfigParamsSchema = config.WithParamsSchema
+
+// ConfigParamsSchemaForStruct generates the JSON Schema of the params loaded by LoadConfigForStruct[T].
+func ConfigParamsSchemaForStruct[T any]() *JSONSchema {
+	return config.ParamsSchemaForStruct[T]()
+}
+
 // ServeConfigRegistryPlugin serves the given config provider registry as a plugin.
 var ServeConfigRegistryPlugin = configplugin.ServeRegistry
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Azure/k6ctl/internal/config"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+const configProviderNameEnv = "env"
+
+type envSettings struct {
+	Name     string `mapstructure:"name" validate:"required" jsonschema_description:"name of the environment variable of k6ctl"`
+	Default  string `mapstructure:"default" jsonschema_description:"value used when the environment variable is not set"`
+	Required bool   `mapstructure:"required" jsonschema_description:"fail the run when the environment variable is not set"`
+}
+
+func (p envSettings) Validate() error {
+	if p.Required && p.Default != "" {
+		return fmt.Errorf("default of environment variable %q is not used when it's required", p.Name)
+	}
+
+	return nil
+}
+
+// createEnvProvider creates the provider reading the environment variables of the k6ctl process.
+func createEnvProvider() config.Provider {
+	return config.Provide(
+		configProviderNameEnv,
+		config.LoadForStruct[envSettings],
+		func(_ context.Context, _ target.Target, params envSettings) (string, error) {
+			if v, ok := os.LookupEnv(params.Name); ok {
+				return v, nil
+			}
+			if params.Required {
+				return "", fmt.Errorf("missing required environment variable %q", params.Name)
+			}
+
+			return params.Default, nil
+		},
+		config.WithParamsSchema(config.ParamsSchemaForStruct[envSettings]()),
+	)
+}
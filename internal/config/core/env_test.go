@@ -0,0 +1,71 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("K6CTL_TEST_TOKEN", "s3cr3t")
+	t.Setenv("K6CTL_TEST_EMPTY", "")
+
+	cases := []struct {
+		name      string
+		params    map[string]any
+		expected  string
+		expectErr string
+	}{
+		{
+			name:     "set",
+			params:   map[string]any{"name": "K6CTL_TEST_TOKEN", "required": true},
+			expected: "s3cr3t",
+		},
+		{
+			name:     "set to empty",
+			params:   map[string]any{"name": "K6CTL_TEST_EMPTY", "default": "fallback"},
+			expected: "",
+		},
+		{
+			name:     "not set with default",
+			params:   map[string]any{"name": "K6CTL_TEST_MISSING", "default": "fallback"},
+			expected: "fallback",
+		},
+		{
+			name:     "not set without default",
+			params:   map[string]any{"name": "K6CTL_TEST_MISSING"},
+			expected: "",
+		},
+		{
+			name:      "not set but required",
+			params:    map[string]any{"name": "K6CTL_TEST_MISSING", "required": true},
+			expectErr: `missing required environment variable "K6CTL_TEST_MISSING"`,
+		},
+		{
+			name:      "required with default",
+			params:    map[string]any{"name": "K6CTL_TEST_TOKEN", "required": true, "default": "fallback"},
+			expectErr: `default of environment variable "K6CTL_TEST_TOKEN" is not used when it's required`,
+		},
+		{
+			name:      "missing name",
+			params:    map[string]any{},
+			expectErr: "Name",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var fakeTarget target.Target
+			actual, err := createEnvProvider().Resolve(context.Background(), fakeTarget, tc.params)
+			if tc.expectErr != "" {
+				assert.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
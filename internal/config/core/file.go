@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/k6ctl/internal/config"
+	"github.com/Azure/k6ctl/internal/stdlib"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+const configProviderNameFile = "file"
+
+type fileSettings struct {
+	Path string `mapstructure:"path" validate:"required" jsonschema_description:"path of the file, relative to the base directory"`
+	// Trim removes the leading and trailing white spaces, like the trailing newline of the file.
+	Trim bool `mapstructure:"trim" jsonschema_description:"trim the leading and trailing white spaces"`
+	// Base64 encodes the content, for binary files. The content is trimmed before encoding.
+	Base64 bool `mapstructure:"base64" jsonschema_description:"encode the content with base64"`
+}
+
+// fileProvider provides the content of the local files in the base directory.
+type fileProvider struct {
+	baseDir string
+}
+
+func (p fileProvider) readFile(_ context.Context, _ target.Target, params fileSettings) (string, error) {
+	absBaseDir, err := filepath.Abs(p.baseDir)
+	if err != nil {
+		return "", fmt.Errorf("invalid base dir %q: %w", p.baseDir, err)
+	}
+	source := filepath.Join(absBaseDir, filepath.FromSlash(params.Path))
+	if filepath.IsAbs(params.Path) || !stdlib.IsChildPath(absBaseDir, source) {
+		return "", fmt.Errorf("file %q is outside of the base directory %q", params.Path, absBaseDir)
+	}
+
+	content, err := os.ReadFile(source)
+	if err != nil {
+		return "", fmt.Errorf("read file %q: %w", params.Path, err)
+	}
+
+	rv := string(content)
+	if params.Trim {
+		rv = strings.TrimSpace(rv)
+	}
+	if params.Base64 {
+		rv = base64.StdEncoding.EncodeToString([]byte(rv))
+	}
+
+	return rv, nil
+}
+
+func (p fileProvider) CreateProvider() config.Provider {
+	return config.Provide(
+		configProviderNameFile,
+		config.LoadForStruct[fileSettings],
+		p.readFile,
+		config.WithParamsSchema(config.ParamsSchemaForStruct[fileSettings]()),
+	)
+}
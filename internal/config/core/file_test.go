@@ -0,0 +1,82 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+func TestFileProvider(t *testing.T) {
+	root := t.TempDir()
+	baseDir := filepath.Join(root, "task")
+	assert.NoError(t, os.MkdirAll(filepath.Join(baseDir, "secrets"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(baseDir, "secrets", "token.txt"), []byte("  s3cr3t\n"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(baseDir, "cert.der"), []byte{0x30, 0x82, 0xff}, 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "outside.txt"), []byte("outside"), 0o644))
+
+	cases := []struct {
+		name      string
+		params    map[string]any
+		expected  string
+		expectErr string
+	}{
+		{
+			name:     "as is",
+			params:   map[string]any{"path": "secrets/token.txt"},
+			expected: "  s3cr3t\n",
+		},
+		{
+			name:     "trim",
+			params:   map[string]any{"path": "./secrets/token.txt", "trim": true},
+			expected: "s3cr3t",
+		},
+		{
+			name:     "base64",
+			params:   map[string]any{"path": "cert.der", "base64": true},
+			expected: "MIL/",
+		},
+		{
+			name:     "trim and base64",
+			params:   map[string]any{"path": "secrets/token.txt", "trim": true, "base64": true},
+			expected: "czNjcjN0",
+		},
+		{
+			name:      "outside of base dir",
+			params:    map[string]any{"path": "../outside.txt"},
+			expectErr: `file "../outside.txt" is outside of the base directory`,
+		},
+		{
+			name:      "absolute path",
+			params:    map[string]any{"path": filepath.Join(root, "outside.txt")},
+			expectErr: "is outside of the base directory",
+		},
+		{
+			name:      "missing file",
+			params:    map[string]any{"path": "missing.txt"},
+			expectErr: `read file "missing.txt"`,
+		},
+		{
+			name:      "missing path",
+			params:    map[string]any{},
+			expectErr: "Path",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var fakeTarget target.Target
+			actual, err := fileProvider{baseDir: baseDir}.CreateProvider().Resolve(context.Background(), fakeTarget, tc.params)
+			if tc.expectErr != "" {
+				assert.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/Azure/k6ctl/internal/config"
+	"github.com/Azure/k6ctl/internal/kubelib"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+const (
+	configProviderNameKubeSecret    = "kubeSecret"
+	configProviderNameKubeConfigMap = "kubeConfigMap"
+)
+
+// kubeKeySettings selects a key of a Secret or ConfigMap in the target cluster.
+type kubeKeySettings struct {
+	Namespace string `mapstructure:"namespace" validate:"required" jsonschema_description:"namespace of the object"`
+	Name      string `mapstructure:"name" validate:"required" jsonschema_description:"name of the object"`
+	Key       string `mapstructure:"key" validate:"required" jsonschema_description:"key in the object data"`
+	// Base64 decodes the value, for values stored base64 encoded.
+	// Secret data is decoded by the API server already.
+	Base64 bool `mapstructure:"base64" jsonschema_description:"decode the value from base64"`
+	// JSONPath extracts a field from the JSON value, e.g. "{.token}".
+	JSONPath string `mapstructure:"jsonPath" jsonschema_description:"kubectl style JSONPath template extracting a field from the JSON value"`
+}
+
+func (p kubeKeySettings) Validate() error {
+	if p.JSONPath != "" {
+		if _, err := parseJSONPath(p.JSONPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseJSONPath parses the kubectl style JSONPath template. The braces can be omitted: ".token" is "{.token}".
+func parseJSONPath(template string) (*jsonpath.JSONPath, error) {
+	if !strings.Contains(template, "{") {
+		template = fmt.Sprintf("{%s}", template)
+	}
+
+	j := jsonpath.New("jsonPath")
+	if err := j.Parse(template); err != nil {
+		return nil, fmt.Errorf("invalid jsonPath %q: %w", template, err)
+	}
+
+	return j, nil
+}
+
+// extract applies the base64 and JSONPath extraction to the value read from the object.
+func (p kubeKeySettings) extract(value []byte) (string, error) {
+	if p.Base64 {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(value)))
+		if err != nil {
+			return "", fmt.Errorf("decode base64 value of key %q: %w", p.Key, err)
+		}
+		value = decoded
+	}
+
+	if p.JSONPath == "" {
+		return string(value), nil
+	}
+
+	var data any
+	if err := json.Unmarshal(value, &data); err != nil {
+		return "", fmt.Errorf("parse value of key %q as JSON: %w", p.Key, err)
+	}
+	j, err := parseJSONPath(p.JSONPath)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := j.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("extract %q from value of key %q: %w", p.JSONPath, p.Key, err)
+	}
+
+	return out.String(), nil
+}
+
+// kubeObjectProviders provides the config providers reading from objects in the target cluster.
+type kubeObjectProviders struct {
+	// kubeClientFactory creates the client from the target kubeconfig.
+	// Unit test can provide a mock implementation.
+	kubeClientFactory kubelib.KubeClientFactory
+}
+
+// kubeClientForTarget creates the client of the target cluster.
+func kubeClientForTarget(kubeClientFactory kubelib.KubeClientFactory, target target.Target) (kubernetes.Interface, error) {
+	kubeconfig, ok := target.GetKubeconfig()
+	if !ok {
+		return nil, fmt.Errorf("target does not have kubeconfig")
+	}
+	kubeClient, err := kubeClientFactory(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	return kubeClient, nil
+}
+
+// readKey reads the key of the object with the given kind in the target cluster.
+func (p kubeObjectProviders) readKey(
+	ctx context.Context,
+	target target.Target,
+	params kubeKeySettings,
+	kind string,
+	get func(ctx context.Context, kubeClient kubernetes.Interface, params kubeKeySettings) (map[string][]byte, error),
+) (string, error) {
+	kubeClient, err := kubeClientForTarget(p.kubeClientFactory, target)
+	if err != nil {
+		return "", err
+	}
+
+	ref := fmt.Sprintf("%s %s/%s", kind, params.Namespace, params.Name)
+	data, err := get(ctx, kubeClient, params)
+	switch {
+	case err == nil:
+	case k8serrors.IsForbidden(err):
+		return "", fmt.Errorf(
+			"access to %s is denied, the kubeconfig user needs the \"get\" permission on %ss in namespace %q: %w",
+			ref, kind, params.Namespace, err,
+		)
+	case k8serrors.IsUnauthorized(err):
+		return "", fmt.Errorf("read %s: the kubeconfig credentials are not authorized: %w", ref, err)
+	case k8serrors.IsNotFound(err):
+		return "", fmt.Errorf("%s not found", ref)
+	default:
+		return "", fmt.Errorf("read %s: %w", ref, err)
+	}
+
+	value, ok := data[params.Key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in %s", params.Key, ref)
+	}
+
+	rv, err := params.extract(value)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", ref, err)
+	}
+
+	return rv, nil
+}
+
+func getSecretData(ctx context.Context, kubeClient kubernetes.Interface, params kubeKeySettings) (map[string][]byte, error) {
+	secret, err := kubeClient.CoreV1().Secrets(params.Namespace).Get(ctx, params.Name, k8smetav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return secret.Data, nil
+}
+
+func getConfigMapData(ctx context.Context, kubeClient kubernetes.Interface, params kubeKeySettings) (map[string][]byte, error) {
+	configMap, err := kubeClient.CoreV1().ConfigMaps(params.Namespace).Get(ctx, params.Name, k8smetav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	rv := make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData))
+	for k, v := range configMap.Data {
+		rv[k] = []byte(v)
+	}
+	for k, v := range configMap.BinaryData {
+		rv[k] = v
+	}
+
+	return rv, nil
+}
+
+func (p kubeObjectProviders) CreateProviders() []config.Provider {
+	return []config.Provider{
+		config.Provide(
+			configProviderNameKubeSecret,
+			config.LoadForStruct[kubeKeySettings],
+			func(ctx context.Context, target target.Target, params kubeKeySettings) (string, error) {
+				return p.readKey(ctx, target, params, "secret", getSecretData)
+			},
+			config.WithParamsSchema(config.ParamsSchemaForStruct[kubeKeySettings]()),
+		),
+		config.Provide(
+			configProviderNameKubeConfigMap,
+			config.LoadForStruct[kubeKeySettings],
+			func(ctx context.Context, target target.Target, params kubeKeySettings) (string, error) {
+				return p.readKey(ctx, target, params, "configmap", getConfigMapData)
+			},
+			config.WithParamsSchema(config.ParamsSchemaForStruct[kubeKeySettings]()),
+		),
+	}
+}
@@ -0,0 +1,191 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/Azure/k6ctl/internal/config"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+func TestKubeObjectProviders(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		&k8scorev1.Secret{
+			ObjectMeta: k8smetav1.ObjectMeta{Namespace: "test", Name: "tokens"},
+			Data: map[string][]byte{
+				"token": []byte("s3cr3t"),
+				"creds": []byte(`{"client":{"id":"app","secret":"xyz"}}`),
+			},
+		},
+		&k8scorev1.ConfigMap{
+			ObjectMeta: k8smetav1.ObjectMeta{Namespace: "test", Name: "settings"},
+			Data: map[string]string{
+				"endpoint": "https://example.com",
+				"encoded":  "aGVsbG8=",
+				"nested":   "eyJsZXZlbCI6ImluZm8ifQ==",
+			},
+			BinaryData: map[string][]byte{
+				"blob": []byte("binary"),
+			},
+		},
+	)
+	kubeClient.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() != "restricted" {
+			return false, nil, nil
+		}
+		return true, nil, k8serrors.NewForbidden(
+			schema.GroupResource{Resource: "secrets"}, "tokens",
+			assert.AnError,
+		)
+	})
+
+	registry := config.NewRegistry()
+	kp := kubeObjectProviders{
+		kubeClientFactory: func(kubeConfigPath string) (kubernetes.Interface, error) {
+			assert.Equal(t, "/tmp/kubeconfig", kubeConfigPath)
+			return kubeClient, nil
+		},
+	}
+	for _, provider := range kp.CreateProviders() {
+		registry.Register(provider)
+	}
+
+	resolve := func(providerName string, userInput map[string]any) (string, error) {
+		provider, ok := registry.GetByName(providerName)
+		if !assert.True(t, ok) {
+			return "", nil
+		}
+		return provider.Resolve(context.Background(), &target.StaticTarget{Kubeconfig: "/tmp/kubeconfig"}, userInput)
+	}
+
+	cases := []struct {
+		name      string
+		provider  string
+		params    map[string]any
+		expected  string
+		expectErr string
+	}{
+		{
+			name:     "secret key",
+			provider: configProviderNameKubeSecret,
+			params:   map[string]any{"namespace": "test", "name": "tokens", "key": "token"},
+			expected: "s3cr3t",
+		},
+		{
+			name:     "secret key with json path",
+			provider: configProviderNameKubeSecret,
+			params:   map[string]any{"namespace": "test", "name": "tokens", "key": "creds", "jsonPath": "{.client.secret}"},
+			expected: "xyz",
+		},
+		{
+			name:     "json path without braces",
+			provider: configProviderNameKubeSecret,
+			params:   map[string]any{"namespace": "test", "name": "tokens", "key": "creds", "jsonPath": ".client.id"},
+			expected: "app",
+		},
+		{
+			name:     "configmap key",
+			provider: configProviderNameKubeConfigMap,
+			params:   map[string]any{"namespace": "test", "name": "settings", "key": "endpoint"},
+			expected: "https://example.com",
+		},
+		{
+			name:     "configmap binary key",
+			provider: configProviderNameKubeConfigMap,
+			params:   map[string]any{"namespace": "test", "name": "settings", "key": "blob"},
+			expected: "binary",
+		},
+		{
+			name:     "configmap base64 key",
+			provider: configProviderNameKubeConfigMap,
+			params:   map[string]any{"namespace": "test", "name": "settings", "key": "encoded", "base64": true},
+			expected: "hello",
+		},
+		{
+			name:     "configmap base64 key with json path",
+			provider: configProviderNameKubeConfigMap,
+			params:   map[string]any{"namespace": "test", "name": "settings", "key": "nested", "base64": true, "jsonPath": ".level"},
+			expected: "info",
+		},
+		{
+			name:      "invalid base64",
+			provider:  configProviderNameKubeConfigMap,
+			params:    map[string]any{"namespace": "test", "name": "settings", "key": "endpoint", "base64": true},
+			expectErr: `configmap test/settings: decode base64 value of key "endpoint"`,
+		},
+		{
+			name:      "value is not json",
+			provider:  configProviderNameKubeSecret,
+			params:    map[string]any{"namespace": "test", "name": "tokens", "key": "token", "jsonPath": ".id"},
+			expectErr: `secret test/tokens: parse value of key "token" as JSON`,
+		},
+		{
+			name:      "json path not found",
+			provider:  configProviderNameKubeSecret,
+			params:    map[string]any{"namespace": "test", "name": "tokens", "key": "creds", "jsonPath": ".missing"},
+			expectErr: `extract ".missing" from value of key "creds"`,
+		},
+		{
+			name:      "invalid json path",
+			provider:  configProviderNameKubeSecret,
+			params:    map[string]any{"namespace": "test", "name": "tokens", "key": "creds", "jsonPath": "{.client"},
+			expectErr: `invalid jsonPath "{.client"`,
+		},
+		{
+			name:      "missing key",
+			provider:  configProviderNameKubeSecret,
+			params:    map[string]any{"namespace": "test", "name": "tokens", "key": "missing"},
+			expectErr: `key "missing" not found in secret test/tokens`,
+		},
+		{
+			name:      "missing object",
+			provider:  configProviderNameKubeConfigMap,
+			params:    map[string]any{"namespace": "test", "name": "missing", "key": "endpoint"},
+			expectErr: `configmap test/missing not found`,
+		},
+		{
+			name:      "forbidden",
+			provider:  configProviderNameKubeSecret,
+			params:    map[string]any{"namespace": "restricted", "name": "tokens", "key": "token"},
+			expectErr: `access to secret restricted/tokens is denied, the kubeconfig user needs the "get" permission on secrets in namespace "restricted"`,
+		},
+		{
+			name:      "missing params",
+			provider:  configProviderNameKubeSecret,
+			params:    map[string]any{"namespace": "test", "name": "tokens"},
+			expectErr: "Key",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := resolve(tc.provider, tc.params)
+			if tc.expectErr != "" {
+				assert.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+	t.Run("target without kubeconfig", func(t *testing.T) {
+		provider, _ := registry.GetByName(configProviderNameKubeSecret)
+		_, err := provider.Resolve(
+			context.Background(),
+			&target.StaticTarget{},
+			map[string]any{"namespace": "test", "name": "tokens", "key": "token"},
+		)
+		assert.ErrorContains(t, err, "target does not have kubeconfig")
+	})
+}
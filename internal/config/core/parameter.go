@@ -22,7 +22,7 @@ const (
 
 type parameterSettings struct {
 	Name      string `mapstructure:"name" validate:"required"`
-	OnMissing string `mapstructure:"onMissing"`
+	OnMissing string `mapstructure:"onMissing" jsonschema:"enum=error,enum=prompt,enum=empty"`
 }
 
 func loadParameterSettings(cp task.ConfigProvider) (parameterSettings, error) {
@@ -172,5 +172,6 @@ func (p resolvedParameters) CreateProvider() config.Provider {
 			// NOTE: prompt is done in previous stage
 			return "", fmt.Errorf("missing required parameter %q", params.Name)
 		},
+		config.WithParamsSchema(config.ParamsSchemaForStruct[parameterSettings]()),
 	)
 }
@@ -1,16 +1,61 @@
 package core
 
 import (
+	"slices"
+	"time"
+
 	"github.com/Azure/k6ctl/internal/config"
+	"github.com/Azure/k6ctl/internal/kubelib"
 	"github.com/Azure/k6ctl/internal/task"
 )
 
+type registerOption struct {
+	// ExpectedRunDuration is how long a run is expected to take from resolving the configs,
+	// including the time for starting the instances.
+	// It is the default expiry of the service account tokens. Zero means unknown.
+	ExpectedRunDuration time.Duration
+	// BaseDir is the directory the files read by the "file" provider are relative to.
+	// Defaults to the working directory.
+	BaseDir string
+}
+
+// RegisterOption configures the core config providers.
+type RegisterOption interface {
+	apply(option *registerOption)
+}
+
+type applyRegisterOptionFunc func(option *registerOption)
+
+func (f applyRegisterOptionFunc) apply(option *registerOption) {
+	f(option)
+}
+
+// WithExpectedRunDuration specifies how long a run is expected to take, see task.ExpectedRunDuration.
+func WithExpectedRunDuration(d time.Duration) RegisterOption {
+	return applyRegisterOptionFunc(func(option *registerOption) {
+		option.ExpectedRunDuration = d
+	})
+}
+
+// WithBaseDir specifies the base directory of the task.
+func WithBaseDir(dir string) RegisterOption {
+	return applyRegisterOptionFunc(func(option *registerOption) {
+		option.BaseDir = dir
+	})
+}
+
 // RegisterProviders registers the core config providers.
 func RegisterProviders(
 	registry config.ProviderRegistry,
 	configProviders []task.ConfigProvider,
 	userParameterInputs map[string]string,
+	opts ...RegisterOption,
 ) error {
+	option := &registerOption{}
+	for _, opt := range opts {
+		opt.apply(option)
+	}
+
 	// "parameter" config provider
 	p, err := resolveParameters(configProviders, userParameterInputs)
 	if err != nil {
@@ -18,5 +63,36 @@ func RegisterProviders(
 	}
 	registry.Register(p.CreateProvider())
 
+	// "kubeSecret" and "kubeConfigMap" config providers
+	kp := kubeObjectProviders{kubeClientFactory: kubelib.CreateKubeClientFromKubeConfig}
+	for _, provider := range kp.CreateProviders() {
+		registry.Register(provider)
+	}
+
+	// "serviceAccountToken" config provider
+	sp := serviceAccountTokenProvider{
+		kubeClientFactory:   kubelib.CreateKubeClientFromKubeConfig,
+		expectedRunDuration: option.ExpectedRunDuration,
+	}
+	registry.Register(sp.CreateProvider())
+
+	// "env" and "file" config providers
+	registry.Register(createEnvProvider())
+	registry.Register(fileProvider{baseDir: option.BaseDir}.CreateProvider())
+
 	return nil
 }
+
+// localProviderNames are the core config providers resolved without accessing the target cluster.
+var localProviderNames = []string{configProviderNameParameter, configProviderNameEnv, configProviderNameFile}
+
+// RequiresKubeconfig reports whether resolving the configs may access the target cluster,
+// that is, they use a cluster-backed core provider or a provider from a config plugin.
+func RequiresKubeconfig(configProviders []task.ConfigProvider) bool {
+	for _, c := range configProviders {
+		if !slices.Contains(localProviderNames, c.Provider.Name) {
+			return true
+		}
+	}
+	return false
+}
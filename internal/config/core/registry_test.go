@@ -0,0 +1,26 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Azure/k6ctl/internal/task"
+)
+
+func TestRequiresKubeconfig(t *testing.T) {
+	configs := func(names ...string) []task.ConfigProvider {
+		var rv []task.ConfigProvider
+		for _, name := range names {
+			rv = append(rv, task.ConfigProvider{Provider: task.ConfigProviderProviderSpec{Name: name}})
+		}
+		return rv
+	}
+
+	assert.False(t, RequiresKubeconfig(nil))
+	assert.False(t, RequiresKubeconfig(configs("parameter", "env", "file")))
+	assert.True(t, RequiresKubeconfig(configs("env", "kubeSecret")))
+	assert.True(t, RequiresKubeconfig(configs("serviceAccountToken")))
+	// providers of config plugins may access the cluster
+	assert.True(t, RequiresKubeconfig(configs("myplugin.token")))
+}
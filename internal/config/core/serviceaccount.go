@@ -0,0 +1,129 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	k8sauthenticationv1 "k8s.io/api/authentication/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/k6ctl/internal/config"
+	"github.com/Azure/k6ctl/internal/kubelib"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+const configProviderNameServiceAccountToken = "serviceAccountToken"
+
+const (
+	// defaultServiceAccountTokenExpiry is the expiry used when the expected run duration is unknown.
+	defaultServiceAccountTokenExpiry = time.Hour
+	// minServiceAccountTokenExpiry is the shortest expiry accepted by the TokenRequest API.
+	minServiceAccountTokenExpiry = 10 * time.Minute
+)
+
+type serviceAccountTokenSettings struct {
+	Namespace      string   `mapstructure:"namespace" validate:"required" jsonschema_description:"namespace of the service account"`
+	ServiceAccount string   `mapstructure:"serviceAccount" validate:"required" jsonschema_description:"name of the service account"`
+	Audiences      []string `mapstructure:"audiences" jsonschema_description:"intended audiences of the token, defaults to the audiences of the API server"`
+	// Expiry defaults to the expected run duration.
+	Expiry string `mapstructure:"expiry" jsonschema_description:"how long the token is valid, e.g. 1h. Defaults to k6.expectedDuration plus the time for starting the instances, or 1h"`
+}
+
+func (p serviceAccountTokenSettings) Validate() error {
+	if p.Expiry == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(p.Expiry)
+	if err != nil {
+		return fmt.Errorf("invalid expiry %q: %w", p.Expiry, err)
+	}
+	if d < minServiceAccountTokenExpiry {
+		return fmt.Errorf("invalid expiry %q: must be at least %s", p.Expiry, minServiceAccountTokenExpiry)
+	}
+
+	return nil
+}
+
+// serviceAccountTokenProvider provides short-lived tokens of service accounts in the target cluster
+// with the TokenRequest API.
+type serviceAccountTokenProvider struct {
+	// kubeClientFactory creates the client from the target kubeconfig.
+	// Unit test can provide a mock implementation.
+	kubeClientFactory kubelib.KubeClientFactory
+	// expectedRunDuration is the default expiry of the tokens, which are created before the instances are started.
+	// Zero means unknown.
+	expectedRunDuration time.Duration
+}
+
+// expiry returns the expiry of the token requested with the params.
+func (p serviceAccountTokenProvider) expiry(params serviceAccountTokenSettings) time.Duration {
+	if params.Expiry != "" {
+		// validated by serviceAccountTokenSettings.Validate
+		d, _ := time.ParseDuration(params.Expiry)
+		return d
+	}
+
+	switch {
+	case p.expectedRunDuration <= 0:
+		return defaultServiceAccountTokenExpiry
+	case p.expectedRunDuration < minServiceAccountTokenExpiry:
+		return minServiceAccountTokenExpiry
+	default:
+		return p.expectedRunDuration
+	}
+}
+
+func (p serviceAccountTokenProvider) createToken(
+	ctx context.Context,
+	target target.Target,
+	params serviceAccountTokenSettings,
+) (string, error) {
+	kubeClient, err := kubeClientForTarget(p.kubeClientFactory, target)
+	if err != nil {
+		return "", err
+	}
+
+	expirationSeconds := int64(p.expiry(params).Seconds())
+	tokenRequest := &k8sauthenticationv1.TokenRequest{
+		Spec: k8sauthenticationv1.TokenRequestSpec{
+			Audiences:         params.Audiences,
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}
+
+	ref := fmt.Sprintf("service account %s/%s", params.Namespace, params.ServiceAccount)
+	resp, err := kubeClient.CoreV1().ServiceAccounts(params.Namespace).
+		CreateToken(ctx, params.ServiceAccount, tokenRequest, k8smetav1.CreateOptions{})
+	switch {
+	case err == nil:
+	case k8serrors.IsForbidden(err):
+		return "", fmt.Errorf(
+			"access to %s is denied, the kubeconfig user needs the \"create\" permission on serviceaccounts/token in namespace %q: %w",
+			ref, params.Namespace, err,
+		)
+	case k8serrors.IsUnauthorized(err):
+		return "", fmt.Errorf("create token of %s: the kubeconfig credentials are not authorized: %w", ref, err)
+	case k8serrors.IsNotFound(err):
+		return "", fmt.Errorf("%s not found", ref)
+	default:
+		return "", fmt.Errorf("create token of %s: %w", ref, err)
+	}
+
+	if resp.Status.Token == "" {
+		return "", fmt.Errorf("create token of %s: empty token returned", ref)
+	}
+
+	return resp.Status.Token, nil
+}
+
+func (p serviceAccountTokenProvider) CreateProvider() config.Provider {
+	return config.Provide(
+		configProviderNameServiceAccountToken,
+		config.LoadForStruct[serviceAccountTokenSettings],
+		p.createToken,
+		config.WithParamsSchema(config.ParamsSchemaForStruct[serviceAccountTokenSettings]()),
+	)
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	k8sauthenticationv1 "k8s.io/api/authentication/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+func TestServiceAccountTokenProvider(t *testing.T) {
+	var requests []*k8sauthenticationv1.TokenRequest
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		createAction := action.(k8stesting.CreateActionImpl)
+		if createAction.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+
+		switch createAction.GetNamespace() {
+		case "restricted":
+			return true, nil, k8serrors.NewForbidden(
+				schema.GroupResource{Resource: "serviceaccounts"}, createAction.Name,
+				assert.AnError,
+			)
+		case "missing":
+			return true, nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "serviceaccounts"}, createAction.Name)
+		}
+
+		request := createAction.GetObject().(*k8sauthenticationv1.TokenRequest)
+		requests = append(requests, request)
+		rv := request.DeepCopy()
+		rv.Status.Token = "token-of-" + createAction.Name
+		return true, rv, nil
+	})
+
+	resolve := func(
+		t *testing.T,
+		expectedRunDuration time.Duration,
+		userInput map[string]any,
+	) (string, *k8sauthenticationv1.TokenRequest, error) {
+		t.Helper()
+
+		requests = nil
+		p := serviceAccountTokenProvider{
+			kubeClientFactory: func(kubeConfigPath string) (kubernetes.Interface, error) {
+				return kubeClient, nil
+			},
+			expectedRunDuration: expectedRunDuration,
+		}
+		token, err := p.CreateProvider().Resolve(
+			context.Background(),
+			&target.StaticTarget{Kubeconfig: "/tmp/kubeconfig"},
+			userInput,
+		)
+		if len(requests) == 0 {
+			return token, nil, err
+		}
+		return token, requests[0], err
+	}
+
+	t.Run("expiry defaults to the expected run duration", func(t *testing.T) {
+		token, request, err := resolve(t, 30*time.Minute, map[string]any{
+			"namespace":      "test",
+			"serviceAccount": "loadtest",
+			"audiences":      []any{"api://orders"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "token-of-loadtest", token)
+		if assert.NotNil(t, request) {
+			assert.Equal(t, []string{"api://orders"}, request.Spec.Audiences)
+			assert.Equal(t, int64(1800), *request.Spec.ExpirationSeconds)
+		}
+	})
+
+	t.Run("explicit expiry", func(t *testing.T) {
+		_, request, err := resolve(t, 30*time.Minute, map[string]any{
+			"namespace":      "test",
+			"serviceAccount": "loadtest",
+			"expiry":         "2h",
+		})
+		assert.NoError(t, err)
+		if assert.NotNil(t, request) {
+			assert.Empty(t, request.Spec.Audiences)
+			assert.Equal(t, int64(7200), *request.Spec.ExpirationSeconds)
+		}
+	})
+
+	t.Run("expiry without expected run duration", func(t *testing.T) {
+		_, request, err := resolve(t, 0, map[string]any{"namespace": "test", "serviceAccount": "loadtest"})
+		assert.NoError(t, err)
+		if assert.NotNil(t, request) {
+			assert.Equal(t, int64(3600), *request.Spec.ExpirationSeconds)
+		}
+	})
+
+	t.Run("short expected run duration", func(t *testing.T) {
+		_, request, err := resolve(t, time.Minute, map[string]any{"namespace": "test", "serviceAccount": "loadtest"})
+		assert.NoError(t, err)
+		if assert.NotNil(t, request) {
+			assert.Equal(t, int64(600), *request.Spec.ExpirationSeconds)
+		}
+	})
+
+	t.Run("invalid expiry", func(t *testing.T) {
+		_, _, err := resolve(t, 0, map[string]any{"namespace": "test", "serviceAccount": "loadtest", "expiry": "1m"})
+		assert.ErrorContains(t, err, `invalid expiry "1m": must be at least 10m0s`)
+
+		_, _, err = resolve(t, 0, map[string]any{"namespace": "test", "serviceAccount": "loadtest", "expiry": "soon"})
+		assert.ErrorContains(t, err, `invalid expiry "soon"`)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		_, _, err := resolve(t, 0, map[string]any{"namespace": "restricted", "serviceAccount": "loadtest"})
+		assert.ErrorContains(
+			t, err,
+			`access to service account restricted/loadtest is denied, the kubeconfig user needs the "create" permission on serviceaccounts/token in namespace "restricted"`,
+		)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, _, err := resolve(t, 0, map[string]any{"namespace": "missing", "serviceAccount": "loadtest"})
+		assert.ErrorContains(t, err, "service account missing/loadtest not found")
+	})
+
+	t.Run("missing params", func(t *testing.T) {
+		_, _, err := resolve(t, 0, map[string]any{"namespace": "test"})
+		assert.ErrorContains(t, err, "ServiceAccount")
+	})
+}
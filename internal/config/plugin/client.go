@@ -2,6 +2,7 @@ package plugin
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os/exec"
@@ -10,6 +11,7 @@ import (
 	"github.com/hashicorp/go-plugin"
 
 	"github.com/Azure/k6ctl/internal/config"
+	"github.com/Azure/k6ctl/internal/jsonschema"
 	"github.com/Azure/k6ctl/internal/target"
 )
 
@@ -17,6 +19,7 @@ func remoteNamespacedConfigProvider(
 	namespace string,
 	name string,
 	impl Interface,
+	paramsSchema *jsonschema.Schema,
 ) config.Provider {
 	return config.Provide[ResolveRequest](
 		fmt.Sprintf("%s/%s", namespace, name),
@@ -42,9 +45,30 @@ func remoteNamespacedConfigProvider(
 		func(ctx context.Context, target target.Target, params ResolveRequest) (string, error) {
 			return impl.Resolve(params)
 		},
+		config.WithParamsSchema(paramsSchema),
 	)
 }
 
+// getParamsSchemas gets the params schemas of the plugin providers.
+// Params schemas are optional, so plugins failing to provide them are treated as having none.
+func getParamsSchemas(impl Interface) map[string]*jsonschema.Schema {
+	rv := map[string]*jsonschema.Schema{}
+
+	encoded, err := impl.GetParamsSchemas()
+	if err != nil {
+		// older plugins don't implement GetParamsSchemas
+		return rv
+	}
+	for name, s := range encoded {
+		schema := new(jsonschema.Schema)
+		if err := json.Unmarshal([]byte(s), schema); err != nil {
+			continue
+		}
+		rv[name] = schema
+	}
+	return rv
+}
+
 // ClientBinarySettings defines a namespaced plugin binary.
 // The binary is expected to be used as a remote plugin.
 type ClientBinarySettings struct {
@@ -126,8 +150,9 @@ func registerFromClientBinary(
 		return errOut(err)
 	}
 
+	paramsSchemas := getParamsSchemas(p)
 	for _, name := range supportedProviderNames {
-		reg.Register(remoteNamespacedConfigProvider(settings.Namespace, name, p))
+		reg.Register(remoteNamespacedConfigProvider(settings.Namespace, name, p, paramsSchemas[name]))
 	}
 
 	return stop, nil
@@ -25,6 +25,12 @@ func (g *rpcServer) Resolve(req ResolveRequest, resp *string) error {
 	return err
 }
 
+func (g *rpcServer) GetParamsSchemas(args interface{}, resp *map[string]string) error {
+	r, err := g.Impl.GetParamsSchemas()
+	*resp = r
+	return err
+}
+
 type rpcClient struct{ client *rpc.Client }
 
 func (g *rpcClient) GetNames() ([]string, error) {
@@ -38,3 +44,9 @@ func (g *rpcClient) Resolve(req ResolveRequest) (string, error) {
 	err := g.client.Call("Plugin.Resolve", req, &resp)
 	return resp, err
 }
+
+func (g *rpcClient) GetParamsSchemas() (map[string]string, error) {
+	var resp map[string]string
+	err := g.client.Call("Plugin.GetParamsSchemas", new(interface{}), &resp)
+	return resp, err
+}
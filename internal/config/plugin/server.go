@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/hashicorp/go-plugin"
@@ -30,6 +31,18 @@ func (c *registryServer) Resolve(req ResolveRequest) (string, error) {
 	return provider.Resolve(ctx, req.Target(), req.UserInput)
 }
 
+func (c *registryServer) GetParamsSchemas() (map[string]string, error) {
+	rv := map[string]string{}
+	for name, schema := range config.ParamsSchemas(c.registry) {
+		b, err := json.Marshal(schema)
+		if err != nil {
+			return nil, fmt.Errorf("encode params schema of %q: %w", name, err)
+		}
+		rv[name] = string(b)
+	}
+	return rv, nil
+}
+
 // ServeRegistry serves the given registry as a plugin.
 func ServeRegistry(registry config.ProviderRegistry) {
 	plugin.Serve(&plugin.ServeConfig{
@@ -47,6 +47,10 @@ type Interface interface {
 
 	// Resolve resolves a config from a config provider.
 	Resolve(req ResolveRequest) (string, error)
+
+	// GetParamsSchemas returns the JSON encoded params schemas of the config providers by name.
+	// Plugins built before params schemas were introduced don't implement it.
+	GetParamsSchemas() (map[string]string, error)
 }
 
 type Plugin struct {
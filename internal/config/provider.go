@@ -3,27 +3,65 @@ package config
 import (
 	"context"
 
+	"github.com/Azure/k6ctl/internal/jsonschema"
 	"github.com/Azure/k6ctl/internal/target"
 )
 
 type configProvider[T any] struct {
-	name     string
-	loader   LoadAndValidateParams[T]
-	resolver ResolveConfig[T]
+	name         string
+	loader       LoadAndValidateParams[T]
+	resolver     ResolveConfig[T]
+	paramsSchema *jsonschema.Schema
 
 	configInternalImpl
 }
 
+type provideOption struct {
+	// ParamsSchema is the JSON Schema of the provider params.
+	// If not provided, the params are not described.
+	ParamsSchema *jsonschema.Schema
+}
+
+// ProvideOption configures the config provider created by Provide.
+type ProvideOption interface {
+	apply(option *provideOption)
+}
+
+type applyProvideOptionFunc func(option *provideOption)
+
+func (f applyProvideOptionFunc) apply(option *provideOption) {
+	f(option)
+}
+
+// WithParamsSchema specifies the JSON Schema of the provider params.
+func WithParamsSchema(schema *jsonschema.Schema) ProvideOption {
+	return applyProvideOptionFunc(func(option *provideOption) {
+		option.ParamsSchema = schema
+	})
+}
+
+// ParamsSchemaForStruct generates the JSON Schema of the params loaded by LoadForStruct[T].
+func ParamsSchemaForStruct[T any]() *jsonschema.Schema {
+	return jsonschema.For[T]("mapstructure")
+}
+
 // Provide creates a config provider using the loader and resolver functions.
 func Provide[T any](
 	name string,
 	loader LoadAndValidateParams[T],
 	resolver ResolveConfig[T],
+	opts ...ProvideOption,
 ) Provider {
+	option := &provideOption{}
+	for _, opt := range opts {
+		opt.apply(option)
+	}
+
 	return &configProvider[T]{
-		name:     name,
-		loader:   loader,
-		resolver: resolver,
+		name:         name,
+		loader:       loader,
+		resolver:     resolver,
+		paramsSchema: option.ParamsSchema,
 	}
 }
 
@@ -33,6 +71,10 @@ func (c *configProvider[T]) Name() string {
 	return c.name
 }
 
+func (c *configProvider[T]) ParamsSchema() *jsonschema.Schema {
+	return c.paramsSchema
+}
+
 func (c *configProvider[T]) Resolve(
 	ctx context.Context,
 	target target.Target,
@@ -1,5 +1,7 @@
 package config
 
+import "github.com/Azure/k6ctl/internal/jsonschema"
+
 type registry struct {
 	providers map[string]Provider
 }
@@ -30,3 +32,19 @@ func (r *registry) GetNames() []string {
 	}
 	return names
 }
+
+// ParamsSchemas returns the params schemas of the registered providers by provider name.
+// Providers without params schema are omitted.
+func ParamsSchemas(registry ProviderRegistry) map[string]*jsonschema.Schema {
+	rv := map[string]*jsonschema.Schema{}
+	for _, name := range registry.GetNames() {
+		provider, ok := registry.GetByName(name)
+		if !ok {
+			continue
+		}
+		if schema := provider.ParamsSchema(); schema != nil {
+			rv[name] = schema
+		}
+	}
+	return rv
+}
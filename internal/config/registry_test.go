@@ -35,3 +35,27 @@ func TestRegistry_Integration(t *testing.T) {
 	assert.False(t, ok)
 	assert.Nil(t, provider)
 }
+
+func TestParamsSchemas(t *testing.T) {
+	type params struct {
+		Name string `mapstructure:"name" validate:"required"`
+	}
+
+	resolve := func(ctx context.Context, target target.Target, p params) (string, error) {
+		return p.Name, nil
+	}
+	r := NewRegistry().
+		Register(Provide("with-schema", LoadForStruct[params], resolve, WithParamsSchema(ParamsSchemaForStruct[params]()))).
+		Register(Provide("without-schema", LoadForStruct[params], resolve))
+
+	provider, ok := r.GetByName("without-schema")
+	assert.True(t, ok)
+	assert.Nil(t, provider.ParamsSchema())
+
+	schemas := ParamsSchemas(r)
+	assert.Len(t, schemas, 1)
+	if assert.Contains(t, schemas, "with-schema") {
+		assert.Equal(t, []string{"name"}, schemas["with-schema"].Required)
+		assert.Contains(t, schemas["with-schema"].Properties, "name")
+	}
+}
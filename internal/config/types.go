@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 
+	"github.com/Azure/k6ctl/internal/jsonschema"
 	"github.com/Azure/k6ctl/internal/target"
 )
 
@@ -18,6 +19,8 @@ type Provider interface {
 	Name() string
 	// Resolve - resolves the configuration for the given target and user input map.
 	Resolve(ctx context.Context, target target.Target, userInput map[string]any) (string, error)
+	// ParamsSchema - JSON Schema of the params. It's nil if the params are not described.
+	ParamsSchema() *jsonschema.Schema
 
 	configInternal
 }
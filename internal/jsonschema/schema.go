@@ -0,0 +1,176 @@
+// Package jsonschema generates JSON Schemas from Go types.
+package jsonschema
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema. Only the keywords used by k6ctl are supported.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	ID          string `json:"$id,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Const                any                `json:"const,omitempty"`
+
+	AllOf []*Schema `json:"allOf,omitempty"`
+	If    *Schema   `json:"if,omitempty"`
+	Then  *Schema   `json:"then,omitempty"`
+
+	// Not is only used to express the false schema, see False.
+	Not *Schema `json:"not,omitempty"`
+}
+
+// False returns the schema matching nothing, e.g. for disallowing additional properties.
+func False() *Schema {
+	return &Schema{Not: &Schema{}}
+}
+
+const (
+	// tagKeySchema holds the comma separated schema options of a field: required, enum=<value>.
+	tagKeySchema = "jsonschema"
+	// tagKeyDescription holds the description of a field.
+	tagKeyDescription = "jsonschema_description"
+	// tagKeyValidate holds the go-playground/validator rules of a field. required and oneof are honoured.
+	tagKeyValidate = "validate"
+)
+
+// ForType generates the schema of the Go type. The property names are read from the tagName
+// struct tag, e.g. json or mapstructure. Structs don't allow additional properties.
+func ForType(t reflect.Type, tagName string) *Schema {
+	r := &reflector{
+		tagName:  tagName,
+		visiting: map[reflect.Type]bool{},
+	}
+	return r.reflect(t)
+}
+
+// For generates the schema of the type T, see ForType.
+func For[T any](tagName string) *Schema {
+	return ForType(reflect.TypeOf((*T)(nil)).Elem(), tagName)
+}
+
+type reflector struct {
+	tagName  string
+	visiting map[reflect.Type]bool
+}
+
+func (r *reflector) reflect(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.reflect(t.Elem())}
+	case reflect.Map:
+		rv := &Schema{Type: "object"}
+		if t.Elem().Kind() != reflect.Interface {
+			rv.AdditionalProperties = r.reflect(t.Elem())
+		}
+		return rv
+	case reflect.Struct:
+		if r.visiting[t] {
+			// recursive types are not expanded
+			return &Schema{Type: "object"}
+		}
+		r.visiting[t] = true
+		defer delete(r.visiting, t)
+
+		rv := &Schema{
+			Type:                 "object",
+			Properties:           map[string]*Schema{},
+			AdditionalProperties: False(),
+		}
+		r.reflectFields(rv, t)
+		sort.Strings(rv.Required)
+		return rv
+	default:
+		// any value
+		return &Schema{}
+	}
+}
+
+func (r *reflector) reflectFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get(r.tagName), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			// embedded structs are inlined, like mapstructure's squash and encoding/json
+			if r.tagName == "json" || strings.Contains(opts, "squash") {
+				r.reflectFields(s, field.Type)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := r.reflect(field.Type)
+		prop.Description = field.Tag.Get(tagKeyDescription)
+		required := r.applyFieldOptions(prop, field.Tag)
+		if required {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = prop
+	}
+}
+
+// applyFieldOptions applies the schema options from the field tags, and returns whether the field is required.
+func (r *reflector) applyFieldOptions(prop *Schema, tag reflect.StructTag) bool {
+	var required bool
+
+	for _, opt := range strings.Split(tag.Get(tagKeySchema), ",") {
+		key, value, _ := strings.Cut(opt, "=")
+		switch key {
+		case "required":
+			required = true
+		case "enum":
+			prop.Enum = append(prop.Enum, value)
+		}
+	}
+
+	for _, rule := range strings.Split(tag.Get(tagKeyValidate), ",") {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			required = true
+		case "oneof":
+			if len(prop.Enum) > 0 {
+				continue
+			}
+			for _, v := range strings.Fields(value) {
+				prop.Enum = append(prop.Enum, v)
+			}
+		}
+	}
+
+	return required
+}
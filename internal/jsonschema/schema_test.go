@@ -0,0 +1,91 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testEmbedded struct {
+	Embedded string `json:"embedded"`
+}
+
+type testStruct struct {
+	testEmbedded
+
+	Name     string            `json:"name" jsonschema:"required" jsonschema_description:"name of the thing"`
+	Mode     string            `json:"mode" jsonschema:"enum=a,enum=b"`
+	Count    *int32            `json:"count"`
+	Ratio    float64           `json:"ratio"`
+	Enabled  bool              `json:"enabled"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	Extra    map[string]any    `json:"extra"`
+	Value    any               `json:"value"`
+	Ignored  string            `json:"-"`
+	Children []testStruct      `json:"children"`
+	NoTag    string
+
+	unexported string //nolint:unused
+}
+
+type testParams struct {
+	Name      string `mapstructure:"name" validate:"required"`
+	OnMissing string `mapstructure:"onMissing" validate:"omitempty,oneof=error prompt"`
+}
+
+func TestFor(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		s := For[testStruct]("json")
+
+		assert.Equal(t, "object", s.Type)
+		assert.Equal(t, False(), s.AdditionalProperties)
+		assert.Equal(t, []string{"name"}, s.Required)
+		assert.ElementsMatch(t, []string{
+			"embedded", "name", "mode", "count", "ratio", "enabled", "tags", "labels", "extra", "value", "children", "NoTag",
+		}, keys(s.Properties))
+
+		assert.Equal(t, &Schema{Type: "string", Description: "name of the thing"}, s.Properties["name"])
+		assert.Equal(t, []any{"a", "b"}, s.Properties["mode"].Enum)
+		assert.Equal(t, "integer", s.Properties["count"].Type)
+		assert.Equal(t, "number", s.Properties["ratio"].Type)
+		assert.Equal(t, "boolean", s.Properties["enabled"].Type)
+		assert.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, s.Properties["tags"])
+		assert.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}, s.Properties["labels"])
+		assert.Equal(t, &Schema{Type: "object"}, s.Properties["extra"])
+		assert.Equal(t, &Schema{}, s.Properties["value"])
+		// recursive types are not expanded
+		assert.Equal(t, &Schema{Type: "object"}, s.Properties["children"].Items)
+	})
+
+	t.Run("mapstructure with validate rules", func(t *testing.T) {
+		s := For[testParams]("mapstructure")
+
+		assert.Equal(t, []string{"name"}, s.Required)
+		assert.ElementsMatch(t, []string{"name", "onMissing"}, keys(s.Properties))
+		assert.Equal(t, []any{"error", "prompt"}, s.Properties["onMissing"].Enum)
+	})
+
+	t.Run("marshal", func(t *testing.T) {
+		b, err := json.Marshal(For[testParams]("mapstructure"))
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+			"type": "object",
+			"properties": {
+				"name": {"type": "string"},
+				"onMissing": {"type": "string", "enum": ["error", "prompt"]}
+			},
+			"required": ["name"],
+			"additionalProperties": {"not": {}}
+		}`, string(b))
+	})
+}
+
+func keys(m map[string]*Schema) []string {
+	var rv []string
+	for k := range m {
+		rv = append(rv, k)
+	}
+	return rv
+}
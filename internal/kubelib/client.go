@@ -2,6 +2,7 @@ package kubelib
 
 import (
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -10,10 +11,15 @@ type KubeClientFactory func(kubeConfigPath string) (kubernetes.Interface, error)
 
 // CreateKubeClientFromKubeConfig creates a kubernetes client from the kubeconfig file at the given path.
 func CreateKubeClientFromKubeConfig(kubeConfigPath string) (kubernetes.Interface, error) {
-	config, err := clientcmd.BuildConfigFromFlags("", kubeConfigPath)
+	config, err := RESTConfigFromKubeConfig(kubeConfigPath)
 	if err != nil {
 		return nil, err
 	}
 
 	return kubernetes.NewForConfig(config)
 }
+
+// RESTConfigFromKubeConfig loads the REST client config from the kubeconfig file at the given path.
+func RESTConfigFromKubeConfig(kubeConfigPath string) (*rest.Config, error) {
+	return clientcmd.BuildConfigFromFlags("", kubeConfigPath)
+}
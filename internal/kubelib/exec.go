@@ -0,0 +1,74 @@
+package kubelib
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// ExecParams are the parameters for ExecInPod.
+type ExecParams struct {
+	Namespace string
+	PodName   string
+	Container string
+	Command   []string
+	// Stdin is streamed to the command if not nil.
+	Stdin io.Reader
+	// Stdout receives the output of the command. If nil, the output is discarded.
+	Stdout io.Writer
+}
+
+// ExecInPod runs the command in the container of the pod through the API server.
+// The stderr of the command is included in the returned error if the command fails.
+func ExecInPod(
+	ctx context.Context,
+	config *rest.Config,
+	client kubernetes.Interface,
+	params *ExecParams,
+) error {
+	req := client.CoreV1().RESTClient().
+		Post().
+		Namespace(params.Namespace).
+		Resource("pods").
+		Name(params.PodName).
+		SubResource("exec").
+		VersionedParams(&k8scorev1.PodExecOptions{
+			Container: params.Container,
+			Command:   params.Command,
+			Stdin:     params.Stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("exec in pod %s/%s: %w", params.Namespace, params.PodName, err)
+	}
+
+	stdout := params.Stdout
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	var stderr bytes.Buffer
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  params.Stdin,
+		Stdout: stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("exec in pod %s/%s: %w: %s", params.Namespace, params.PodName, err, msg)
+		}
+		return fmt.Errorf("exec in pod %s/%s: %w", params.Namespace, params.PodName, err)
+	}
+
+	return nil
+}
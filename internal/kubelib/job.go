@@ -0,0 +1,20 @@
+package kubelib
+
+import (
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+)
+
+// IsJobFinished returns true if the job has completed or failed.
+func IsJobFinished(job *k8sbatchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Status != k8scorev1.ConditionTrue {
+			continue
+		}
+		if c.Type == k8sbatchv1.JobComplete || c.Type == k8sbatchv1.JobFailed {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,41 @@
+package kubelib
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// K6APIPort is the port of the k6 REST API exposed by the k6 runner.
+const K6APIPort = 6565
+
+// k6ResumeRequestBody is the k6 REST API request body for resuming a paused test.
+// ref: https://grafana.com/docs/k6/latest/misc/k6-rest-api/#update-status
+const k6ResumeRequestBody = `{"data":{"type":"status","id":"default","attributes":{"paused":false}}}`
+
+// ResumeK6Pod resumes the paused k6 test in the pod via the k6 REST API.
+// The request is sent through the API server pod proxy, so no port-forward is needed.
+func ResumeK6Pod(
+	ctx context.Context,
+	client kubernetes.Interface,
+	namespace string,
+	podName string,
+) error {
+	err := client.CoreV1().RESTClient().
+		Patch(types.PatchType("application/json")).
+		Namespace(namespace).
+		Resource("pods").
+		Name(fmt.Sprintf("%s:%d", podName, K6APIPort)).
+		SubResource("proxy").
+		Suffix("v1", "status").
+		Body([]byte(k6ResumeRequestBody)).
+		Do(ctx).
+		Error()
+	if err != nil {
+		return fmt.Errorf("resume k6 in pod %s/%s: %w", namespace, podName, err)
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"sync"
 
 	k8scorev1 "k8s.io/api/core/v1"
@@ -27,6 +28,20 @@ type FollowLogsParams struct {
 	MaxConcurrency int
 	// AddPrefix specifies whether to add the prefix of the pod name to the logs.
 	AddPrefix bool
+	// NoFollow specifies to print the current logs of the existing pods and return,
+	// instead of following the logs of the pods until the context is done.
+	NoFollow bool
+	// Stop, when closed, stops discovering new pods to follow. The logs of the pods being
+	// followed are drained before returning. Optional.
+	Stop <-chan struct{}
+	// PodNames limits the pods to read logs from. Empty means all pods matching the selector.
+	PodNames []string
+	// SinceSeconds limits the logs to the ones newer than the given seconds. Optional.
+	SinceSeconds *int64
+	// TailLines limits the number of lines from the end of the logs to show. Optional.
+	TailLines *int64
+	// SkipLine reports whether the log line should be left out of the output. Optional.
+	SkipLine func(line []byte) bool
 
 	// Output is the writer to write the logs to.
 	Output io.Writer
@@ -64,9 +79,17 @@ type logsFollower struct {
 	container      string
 	maxConcurrency int
 	addPrefix      bool
+	noFollow       bool
+	stop           <-chan struct{}
+	podNames       map[string]struct{}
+	sinceSeconds   *int64
+	tailLines      *int64
+	skipLine       func(line []byte) bool
 	out            io.Writer
 
 	wg *sync.WaitGroup
+	// discoverErr is the error of listing the pods with noFollow, set before wg is done.
+	discoverErr error
 
 	podLogsChan      chan podLog
 	handledTargets   map[k8scorev1.ObjectReference]struct{}
@@ -109,8 +132,15 @@ var logsAblePodPhase = map[k8scorev1.PodPhase]struct{}{
 
 func (f *logsFollower) discoverPods(ctx context.Context) {
 	defer f.wg.Done()
+	defer close(f.podLogsChan)
 
 	shouldHandlePod := func(pod *k8scorev1.Pod) bool {
+		if len(f.podNames) > 0 {
+			if _, ok := f.podNames[pod.Name]; !ok {
+				return false
+			}
+		}
+
 		matchContainer := false
 		for _, container := range pod.Spec.Containers {
 			if container.Name == f.container {
@@ -129,13 +159,67 @@ func (f *logsFollower) discoverPods(ctx context.Context) {
 		return true
 	}
 
-	// TODO: log error
-	_ = func() error {
-		podsClient := f.client.CoreV1().Pods(f.namespace)
+	podsClient := f.client.CoreV1().Pods(f.namespace)
+	listOpts := k8smetav1.ListOptions{
+		LabelSelector: f.selector.String(),
+	}
+
+	handlePod := func(pod *k8scorev1.Pod) bool {
+		if !shouldHandlePod(pod) {
+			return true
+		}
+
+		objectRef := k8scorev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+		}
+		if !f.isNewTarget(objectRef) {
+			return true
+		}
 
-		watch, err := podsClient.Watch(ctx, k8smetav1.ListOptions{
-			LabelSelector: f.selector.String(),
+		select {
+		case <-ctx.Done():
+			return false
+		case f.podLogsChan <- podLog{
+			target: objectRef,
+			request: podsClient.GetLogs(pod.Name, &k8scorev1.PodLogOptions{
+				Container:    f.container,
+				Follow:       !f.noFollow,
+				SinceSeconds: f.sinceSeconds,
+				TailLines:    f.tailLines,
+			}),
+		}:
+			return true
+		}
+	}
+
+	handleExistingPods := func() error {
+		pods, err := podsClient.List(ctx, listOpts)
+		if err != nil {
+			return err
+		}
+		sort.Slice(pods.Items, func(i, j int) bool {
+			return pods.Items[i].Name < pods.Items[j].Name
 		})
+		for idx := range pods.Items {
+			if !handlePod(&pods.Items[idx]) {
+				return nil
+			}
+		}
+		return nil
+	}
+
+	if f.noFollow {
+		if err := handleExistingPods(); err != nil {
+			f.discoverErr = fmt.Errorf("list pods: %w", err)
+		}
+		return
+	}
+
+	// TODO: log error
+	_ = func() error {
+		watch, err := podsClient.Watch(ctx, listOpts)
 		if err != nil {
 			return err
 		}
@@ -145,6 +229,9 @@ func (f *logsFollower) discoverPods(ctx context.Context) {
 			select {
 			case <-ctx.Done():
 				return nil
+			case <-f.stop:
+				// catch up with the pods which might not be observed from the watch yet
+				return handleExistingPods()
 			case event, ok := <-watch.ResultChan():
 				if !ok {
 					return nil
@@ -154,24 +241,8 @@ func (f *logsFollower) discoverPods(ctx context.Context) {
 				if !ok {
 					continue
 				}
-				if !shouldHandlePod(pod) {
-					continue
-				}
-
-				objectRef := k8scorev1.ObjectReference{
-					Kind:      "Pod",
-					Namespace: pod.Namespace,
-					Name:      pod.Name,
-				}
-				if !f.isNewTarget(objectRef) {
-					continue
-				}
-				f.podLogsChan <- podLog{
-					target: objectRef,
-					request: podsClient.GetLogs(pod.Name, &k8scorev1.PodLogOptions{
-						Container: f.container,
-						Follow:    true,
-					}),
+				if !handlePod(pod) {
+					return nil
 				}
 			}
 
@@ -186,7 +257,10 @@ func (f *logsFollower) followPodLogs(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return
-		case p := <-f.podLogsChan:
+		case p, ok := <-f.podLogsChan:
+			if !ok {
+				return
+			}
 			// TODO: log error
 			_ = f.followPodLog(ctx, p)
 		}
@@ -203,7 +277,11 @@ func (w *prefixWriter) Write(p []byte) (n int, err error) {
 }
 
 func (f *logsFollower) followPodLog(ctx context.Context, podLog podLog) error {
-	if _, err := fmt.Fprintf(f.out, "Following logs of %s/%s...\n", podLog.target.Namespace, podLog.target.Name); err != nil {
+	header := "Following logs of"
+	if f.noFollow {
+		header = "Showing logs of"
+	}
+	if _, err := fmt.Fprintf(f.out, "%s %s/%s...\n", header, podLog.target.Namespace, podLog.target.Name); err != nil {
 		return err
 	}
 
@@ -227,8 +305,10 @@ func (f *logsFollower) followPodLog(ctx context.Context, podLog podLog) error {
 
 	for {
 		line, err := r.ReadBytes('\n')
-		if _, writeErr := out.Write(line); writeErr != nil {
-			return writeErr
+		if f.skipLine == nil || !f.skipLine(line) {
+			if _, writeErr := out.Write(line); writeErr != nil {
+				return writeErr
+			}
 		}
 		if err != nil {
 			if err == io.EOF {
@@ -249,6 +329,11 @@ func FollowLogs(
 		return err
 	}
 
+	podNames := map[string]struct{}{}
+	for _, name := range params.PodNames {
+		podNames[name] = struct{}{}
+	}
+
 	pr, pw := io.Pipe()
 	follower := &logsFollower{
 		client:         client,
@@ -257,6 +342,12 @@ func FollowLogs(
 		container:      params.Container,
 		maxConcurrency: params.MaxConcurrency,
 		addPrefix:      params.AddPrefix,
+		noFollow:       params.NoFollow,
+		stop:           params.Stop,
+		podNames:       podNames,
+		sinceSeconds:   params.SinceSeconds,
+		tailLines:      params.TailLines,
+		skipLine:       params.SkipLine,
 		out:            pw,
 
 		wg: new(sync.WaitGroup),
@@ -269,7 +360,8 @@ func FollowLogs(
 	go func() {
 		follower.Start(ctx)
 		follower.Wait()
-		_ = pw.Close()
+		// the error of discovering the pods is returned from reading the logs
+		_ = pw.CloseWithError(follower.discoverErr)
 	}()
 
 	_, err := io.Copy(params.Output, pr)
@@ -0,0 +1,225 @@
+package kubelib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// ValidatePartialObject validates the user provided partial object against the type of dataStruct.
+// Unknown fields and mismatched value types are reported with their path prefixed by basePath.
+func ValidatePartialObject(basePath string, partial map[string]any, dataStruct any) error {
+	t := reflect.TypeOf(dataStruct)
+	errs := validatePartialValue(basePath, partial, t)
+	return errors.Join(errs...)
+}
+
+// StrategicMergeObject applies the strategic merge patch to obj and stores the result in obj.
+func StrategicMergeObject[T any](obj *T, patch map[string]any) error {
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("marshal original object: %w", err)
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("marshal patch: %w", err)
+	}
+
+	var empty T
+	patched, err := strategicpatch.StrategicMergePatch(original, patchBytes, empty)
+	if err != nil {
+		return fmt.Errorf("apply strategic merge patch: %w", err)
+	}
+
+	var rv T
+	if err := json.Unmarshal(patched, &rv); err != nil {
+		return fmt.Errorf("unmarshal patched object: %w", err)
+	}
+	*obj = rv
+
+	return nil
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+func describeValue(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "list"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		if isNumber(v) {
+			return "number"
+		}
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func isNumber(v any) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isInteger(v any) bool {
+	switch n := v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return true
+	case float32:
+		return float64(n) == math.Trunc(float64(n))
+	case float64:
+		return n == math.Trunc(n)
+	default:
+		return false
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func typeMismatch(path string, expected string, v any) error {
+	return fmt.Errorf("%s: expected %s, got %s", path, expected, describeValue(v))
+}
+
+// jsonFields returns the json fields of the struct type, including the inlined ones.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	rv := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && (name == "" || strings.Contains(opts, "inline")) {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k, v := range jsonFields(ft) {
+					rv[k] = v
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		rv[name] = f.Type
+	}
+	return rv
+}
+
+func validatePartialValue(path string, v any, t reflect.Type) []error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if v == nil {
+		// null resets the field
+		return nil
+	}
+
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		// types with custom decoding (quantity, int-or-string, time...)
+		b, err := json.Marshal(v)
+		if err != nil {
+			return []error{fmt.Errorf("%s: %w", path, err)}
+		}
+		if err := json.Unmarshal(b, reflect.New(t).Interface()); err != nil {
+			return []error{fmt.Errorf("%s: %w", path, err)}
+		}
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return []error{typeMismatch(path, "object", v)}
+		}
+		fields := jsonFields(t)
+
+		var errs []error
+		for _, k := range sortedKeys(m) {
+			ft, ok := fields[k]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s.%s: unknown field", path, k))
+				continue
+			}
+			errs = append(errs, validatePartialValue(path+"."+k, m[k], ft)...)
+		}
+		return errs
+	case reflect.Map:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return []error{typeMismatch(path, "object", v)}
+		}
+		var errs []error
+		for _, k := range sortedKeys(m) {
+			errs = append(errs, validatePartialValue(path+"."+k, m[k], t.Elem())...)
+		}
+		return errs
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded as base64 string
+			if _, ok := v.(string); !ok {
+				return []error{typeMismatch(path, "string", v)}
+			}
+			return nil
+		}
+		l, ok := v.([]any)
+		if !ok {
+			return []error{typeMismatch(path, "list", v)}
+		}
+		var errs []error
+		for idx, item := range l {
+			errs = append(errs, validatePartialValue(fmt.Sprintf("%s[%d]", path, idx), item, t.Elem())...)
+		}
+		return errs
+	case reflect.String:
+		if _, ok := v.(string); !ok {
+			return []error{typeMismatch(path, "string", v)}
+		}
+	case reflect.Bool:
+		if _, ok := v.(bool); !ok {
+			return []error{typeMismatch(path, "boolean", v)}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !isInteger(v) {
+			return []error{typeMismatch(path, "integer", v)}
+		}
+	case reflect.Float32, reflect.Float64:
+		if !isNumber(v) {
+			return []error{typeMismatch(path, "number", v)}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package stdlib
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// IsChildPath reports whether childPath is basePath or a path inside of it.
+// Both paths are expected to be absolute.
+func IsChildPath(basePath string, childPath string) bool {
+	relPath, err := filepath.Rel(basePath, childPath)
+	if err != nil {
+		return false
+	}
+	if relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return false
+	}
+	return !filepath.IsAbs(relPath)
+}
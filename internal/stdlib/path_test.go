@@ -0,0 +1,16 @@
+package stdlib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsChildPath(t *testing.T) {
+	assert.True(t, IsChildPath("/base", "/base/script.js"))
+	assert.True(t, IsChildPath("/base", "/base/lib/..data/script.js"))
+	assert.True(t, IsChildPath("/base", "/base/..script.js"))
+	assert.False(t, IsChildPath("/base", "/base/../etc/passwd"))
+	assert.False(t, IsChildPath("/base", "/etc/passwd"))
+	assert.False(t, IsChildPath("/base", "/"))
+}
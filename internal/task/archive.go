@@ -0,0 +1,129 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/k6ctl/internal/stdlib"
+)
+
+// k6ArchiveExt is the extension of the archives created by k6 archive.
+const k6ArchiveExt = ".tar"
+
+const defaultK6Binary = "k6"
+
+// BuildK6Archive bundles the script in dir with its local and remote dependencies to the k6 archive at output.
+type BuildK6Archive func(ctx context.Context, k6Binary string, dir string, script string, output string) error
+
+// buildK6ArchiveWithBinary runs k6 archive with the local k6 binary.
+func buildK6ArchiveWithBinary(ctx context.Context, k6Binary string, dir string, script string, output string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, k6Binary, "archive", script, "--archive-out", output)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s archive: %w: %s", k6Binary, err, msg)
+		}
+		return fmt.Errorf("%s archive: %w", k6Binary, err)
+	}
+	return nil
+}
+
+// isK6Archive reports whether the script is a k6 archive, which is run by k6 as is.
+func isK6Archive(script string) bool {
+	return strings.EqualFold(path.Ext(script), k6ArchiveExt)
+}
+
+// k6ArchivePath returns the path of the archive built from the script in the scripts volume,
+// next to the script with the archive extension.
+func k6ArchivePath(script string) string {
+	script = path.Clean(filepath.ToSlash(script))
+	return strings.TrimSuffix(script, path.Ext(script)) + k6ArchiveExt
+}
+
+// resolveArchiveEntry returns the local path of the script relative to baseDir. The script is the path
+// in the scripts volume, so it's mapped to the source of the file mounted there.
+func resolveArchiveEntry(baseDir string, mounts []FileMount, script string) (string, error) {
+	files, err := resolveFileMounts(baseDir, mounts)
+	if err != nil {
+		return "", err
+	}
+
+	scriptPath := path.Clean(filepath.ToSlash(script))
+	for _, f := range files {
+		if f.Path != scriptPath {
+			continue
+		}
+		rv, err := filepath.Rel(baseDir, f.Source)
+		if err != nil {
+			return "", fmt.Errorf("resolve source of script %q: %w", script, err)
+		}
+		return rv, nil
+	}
+	return "", fmt.Errorf("script %q is not mounted by the files of the task config", script)
+}
+
+// buildScriptArchive builds the k6 archive of the script mounted from baseDir into a temporary directory.
+// It returns the path of the archive, and the func to remove it once the run is created.
+func buildScriptArchive(
+	ctx context.Context,
+	build BuildK6Archive,
+	k6Binary string,
+	baseDir string,
+	mounts []FileMount,
+	script string,
+) (string, func(), error) {
+	if isK6Archive(script) {
+		return "", nil, fmt.Errorf("script %q is already a k6 archive", script)
+	}
+	entry, err := resolveArchiveEntry(baseDir, mounts, script)
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "k6ctl-archive-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+
+	output := filepath.Join(dir, path.Base(k6ArchivePath(script)))
+	if err := build(ctx, k6Binary, baseDir, entry, output); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to build k6 archive of %q: %w", script, err)
+	}
+	return output, cleanup, nil
+}
+
+// resolveArchiveFile returns the k6 archive to mount as the script. The archive is either built by k6ctl
+// to archiveSource, or given as the script relative to baseDir.
+func resolveArchiveFile(baseDir string, script string, archiveSource string) (scriptFile, error) {
+	rv := scriptFile{
+		Source: archiveSource,
+		Path:   path.Clean(filepath.ToSlash(script)),
+		Binary: true,
+	}
+	if rv.Source != "" {
+		return rv, nil
+	}
+
+	absBaseDir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return scriptFile{}, fmt.Errorf("invalid base dir %q: %w", baseDir, err)
+	}
+	rv.Source = filepath.Join(absBaseDir, filepath.FromSlash(rv.Path))
+	if !stdlib.IsChildPath(absBaseDir, rv.Source) {
+		return scriptFile{}, fmt.Errorf("k6 archive %q is outside of the base directory %q", script, absBaseDir)
+	}
+	if stat, err := os.Stat(rv.Source); err != nil || !stat.Mode().IsRegular() {
+		return scriptFile{}, fmt.Errorf("k6 archive %q not found", script)
+	}
+	return rv, nil
+}
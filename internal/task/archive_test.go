@@ -0,0 +1,188 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/Azure/k6ctl/internal/config"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+func TestK6ArchivePath(t *testing.T) {
+	assert.True(t, isK6Archive("run.tar"))
+	assert.True(t, isK6Archive("dist/RUN.TAR"))
+	assert.False(t, isK6Archive("run.js"))
+
+	assert.Equal(t, "run.tar", k6ArchivePath("run.js"))
+	assert.Equal(t, "tests/smoke.tar", k6ArchivePath("./tests/smoke.ts"))
+	assert.Equal(t, "run.tar", k6ArchivePath("run"))
+}
+
+func TestRunTask_Archive(t *testing.T) {
+	archiveContent := []byte("k6 archive\x00\xff")
+
+	render := func(
+		t *testing.T,
+		dir string,
+		files []FileMount,
+		script string,
+		options ...RunTaskOption,
+	) ([]*k8scorev1.ConfigMap, *k8sbatchv1.Job, error) {
+		t.Helper()
+
+		var out bytes.Buffer
+		options = append(options, WithDryRun(&out, false))
+		err := RunTask(
+			context.Background(),
+			&target.StaticTarget{},
+			config.NewRegistry().GetByName,
+			&Schema{Name: "test", Files: files, K6: K6{Namespace: "test"}},
+			dir,
+			script,
+			options...,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var (
+			configMaps []*k8scorev1.ConfigMap
+			job        *k8sbatchv1.Job
+		)
+		for _, doc := range strings.Split(out.String(), "---\n") {
+			var meta struct {
+				Kind string `json:"kind"`
+			}
+			assert.NoError(t, yaml.Unmarshal([]byte(doc), &meta))
+			switch meta.Kind {
+			case "ConfigMap":
+				configMap := new(k8scorev1.ConfigMap)
+				assert.NoError(t, yaml.Unmarshal([]byte(doc), configMap))
+				configMaps = append(configMaps, configMap)
+			case "Job":
+				job = new(k8sbatchv1.Job)
+				assert.NoError(t, yaml.Unmarshal([]byte(doc), job))
+			}
+		}
+		return configMaps, job, nil
+	}
+
+	t.Run("archive as script", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, "dist"), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "dist", "run.tar"), archiveContent, 0o644))
+
+		configMaps, job, err := render(t, dir, nil, "dist/run.tar")
+		assert.NoError(t, err)
+		if assert.Len(t, configMaps, 1) {
+			assert.Equal(t, map[string][]byte{"dist__run.tar": archiveContent}, configMaps[0].BinaryData)
+		}
+		assert.Equal(t, []string{"run", "dist/run.tar"}, job.Spec.Template.Spec.Containers[0].Args)
+	})
+
+	t.Run("archive not found", func(t *testing.T) {
+		_, _, err := render(t, t.TempDir(), nil, "run.tar")
+		assert.ErrorContains(t, err, `k6 archive "run.tar" not found`)
+	})
+
+	t.Run("archive built from script", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "run.js"), []byte(`import "https://jslib.k6.io/k6-utils/1.4.0/index.js";`), 0o644))
+
+		var built []string
+		configMaps, job, err := render(
+			t, dir, []FileMount{{Source: "run.js", Dest: "run.js"}}, "run.js",
+			WithArchive("/opt/k6"),
+			applyRunTaskOptionFunc(func(option *runTaskOption) error {
+				option.BuildK6Archive = func(ctx context.Context, k6Binary string, buildDir string, script string, output string) error {
+					built = append(built, k6Binary, script)
+					assert.Equal(t, dir, buildDir)
+					return os.WriteFile(output, archiveContent, 0o644)
+				}
+				return nil
+			}),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/opt/k6", "run.js"}, built)
+		// the imports are bundled in the archive, so they are not mounted
+		if assert.Len(t, configMaps, 1) {
+			assert.Len(t, configMaps[0].Data, 1)
+			assert.Contains(t, configMaps[0].Data, "run.js")
+			assert.Equal(t, map[string][]byte{"run.tar": archiveContent}, configMaps[0].BinaryData)
+		}
+		assert.Equal(t, []string{"run", "run.tar"}, job.Spec.Template.Spec.Containers[0].Args)
+	})
+
+	t.Run("archive built from mounted source", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "src", "run.js"), []byte("export default function () {}"), 0o644))
+
+		var built []string
+		configMaps, job, err := render(
+			t, dir, []FileMount{{Source: "src/run.js", Dest: "run.js"}}, "run.js",
+			WithArchive("k6"),
+			applyRunTaskOptionFunc(func(option *runTaskOption) error {
+				option.BuildK6Archive = func(ctx context.Context, k6Binary string, buildDir string, script string, output string) error {
+					built = append(built, script)
+					assert.Equal(t, dir, buildDir)
+					return os.WriteFile(output, archiveContent, 0o644)
+				}
+				return nil
+			}),
+		)
+		assert.NoError(t, err)
+		// the local source of the script is archived, and the archive is mounted next to the script
+		assert.Equal(t, []string{filepath.Join("src", "run.js")}, built)
+		if assert.Len(t, configMaps, 1) {
+			assert.Equal(t, archiveContent, configMaps[0].BinaryData["run.tar"])
+		}
+		assert.Equal(t, []string{"run", "run.tar"}, job.Spec.Template.Spec.Containers[0].Args)
+	})
+
+	t.Run("script not mounted", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "run.js"), []byte("export default function () {}"), 0o644))
+
+		_, _, err := render(
+			t, dir, nil, "run.js",
+			WithArchive(""),
+			applyRunTaskOptionFunc(func(option *runTaskOption) error {
+				option.BuildK6Archive = func(ctx context.Context, k6Binary string, dir string, script string, output string) error {
+					t.Fatal("archive should not be built")
+					return nil
+				}
+				return nil
+			}),
+		)
+		assert.ErrorContains(t, err, `script "run.js" is not mounted by the files of the task config`)
+	})
+
+	t.Run("build failure", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "run.js"), []byte("export default function () {}"), 0o644))
+
+		_, _, err := render(
+			t, dir, []FileMount{{Source: "run.js", Dest: "run.js"}}, "run.js",
+			WithArchive(""),
+			applyRunTaskOptionFunc(func(option *runTaskOption) error {
+				option.BuildK6Archive = func(ctx context.Context, k6Binary string, dir string, script string, output string) error {
+					assert.Equal(t, defaultK6Binary, k6Binary)
+					return errors.New("module not found")
+				}
+				return nil
+			}),
+		)
+		assert.ErrorContains(t, err, `failed to build k6 archive of "run.js": module not found`)
+	})
+}
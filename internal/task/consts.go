@@ -1,8 +1,65 @@
 package task
 
 const (
-	labelKeyTaskName     = "k6ctl/task"
+	labelKeyTaskName = "k6ctl/task"
+	labelKeyRunID    = "k6ctl/run-id"
+	// labelKeyJobName is set on the pods of a job by the job controller.
+	labelKeyJobName      = "job-name"
 	scriptsVolumeName    = "k6-scripts"
 	containerScriptsPath = "/scripts"
 	containerNameRunner  = "k6-runner"
 )
+
+const (
+	segmentsVolumeName    = "k6ctl-segments"
+	containerSegmentsPath = "/k6ctl/segments"
+	// envKeyInstanceIndex is the env var holding the completion index of the pod in an indexed job.
+	envKeyInstanceIndex = "K6CTL_INSTANCE_INDEX"
+)
+
+const (
+	// initContainerNameAssets is the init container receiving the files uploaded by k6ctl.
+	initContainerNameAssets = "k6ctl-assets"
+	// scriptsSourceVolumeName is the volume of the files mounted from ConfigMaps,
+	// copied to the scripts volume by the assets init container.
+	scriptsSourceVolumeName    = "k6ctl-scripts-source"
+	containerScriptsSourcePath = "/k6ctl/scripts-source"
+)
+
+const (
+	summaryVolumeName    = "k6ctl-summary"
+	containerSummaryPath = "/k6ctl/summary"
+)
+
+const (
+	// DistributionReplicate runs the full test script in every instance.
+	DistributionReplicate = "replicate"
+	// DistributionSegments splits the test load across instances with k6 execution segments.
+	DistributionSegments = "segments"
+)
+
+const (
+	// placementModeRequired schedules each instance to a different topology domain, or leaves it pending.
+	placementModeRequired = "required"
+	// placementModePreferred spreads the instances across topology domains on a best-effort basis.
+	placementModePreferred = "preferred"
+	// placementModeSpread uses topology spread constraints to balance the instances across topology domains.
+	placementModeSpread = "spread"
+	// placementModeNone does not constrain the placement of the instances.
+	placementModeNone = "none"
+
+	defaultPlacementTopologyKey = "kubernetes.io/hostname"
+)
+
+const (
+	controllerKindJob     = "Job"
+	controllerKindCronJob = "CronJob"
+)
+
+// configProviderNameServiceAccountToken is the name of the built-in config provider creating service account tokens,
+// which expire before the later runs of a CronJob.
+const configProviderNameServiceAccountToken = "serviceAccountToken"
+
+// annotationKeyCronJobInstantiate marks a job created from a CronJob manually.
+// ref: https://github.com/kubernetes/kubectl/blob/master/pkg/cmd/create/create_job.go
+const annotationKeyCronJobInstantiate = "cronjob.kubernetes.io/instantiate"
@@ -0,0 +1,241 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/Azure/k6ctl/internal/stdlib"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+// DeleteTask deletes all objects created for the task.
+// Objects are selected by the task name and run ID labels, a run scheduled by a CronJob is selected by its job name.
+// The deleted objects are returned.
+func DeleteTask(
+	ctx context.Context,
+	target target.Target,
+	taskRef TaskRef,
+	options ...DeleteTaskOption,
+) ([]k8scorev1.ObjectReference, error) {
+	opt := defaultDeleteTaskOption()
+	for _, o := range options {
+		if err := o.apply(opt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := taskRef.validate(); err != nil {
+		return nil, err
+	}
+
+	kubeconfig, ok := target.GetKubeconfig()
+	if !ok {
+		return nil, fmt.Errorf("target does not have kubeconfig")
+	}
+	kubeClient, err := opt.KubeClientFactory(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	selector, err := resolveRunSelector(ctx, kubeClient, taskRef)
+	if err != nil {
+		return nil, err
+	}
+
+	td := &taskDeleter{
+		kubeClient: kubeClient,
+		namespace:  taskRef.Namespace,
+		selector:   selector,
+	}
+
+	deleted, err := td.Delete(ctx)
+	if err != nil {
+		return deleted, err
+	}
+
+	if opt.Wait {
+		waitCtx := ctx
+		if opt.WaitTimeout > 0 {
+			var cancel context.CancelFunc
+			waitCtx, cancel = context.WithTimeout(ctx, opt.WaitTimeout)
+			defer cancel()
+		}
+		if err := td.WaitForRemoval(waitCtx, opt.WaitInterval); err != nil {
+			return deleted, err
+		}
+	}
+
+	return deleted, nil
+}
+
+type taskDeleter struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	selector   runSelector
+}
+
+// deleteClient is the subset of typed clients used for deleting task objects.
+type deleteClient interface {
+	Delete(ctx context.Context, name string, opts k8smetav1.DeleteOptions) error
+}
+
+// taskObjectKind describes a kind of object created for a task.
+type taskObjectKind struct {
+	kind string
+	// selector is the label selector of the objects to delete
+	selector string
+	names    func(ctx context.Context, opts k8smetav1.ListOptions) ([]string, error)
+	// client is the delete client for the kind
+	client deleteClient
+}
+
+func listNames[T any](
+	list func(ctx context.Context, opts k8smetav1.ListOptions) (T, error),
+	names func(T) []string,
+) func(ctx context.Context, opts k8smetav1.ListOptions) ([]string, error) {
+	return func(ctx context.Context, opts k8smetav1.ListOptions) ([]string, error) {
+		l, err := list(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return names(l), nil
+	}
+}
+
+func (td *taskDeleter) objectKinds() []taskObjectKind {
+	cronJobsClient := td.kubeClient.BatchV1().CronJobs(td.namespace)
+	jobsClient := td.kubeClient.BatchV1().Jobs(td.namespace)
+	podsClient := td.kubeClient.CoreV1().Pods(td.namespace)
+	secretsClient := td.kubeClient.CoreV1().Secrets(td.namespace)
+	configMapsClient := td.kubeClient.CoreV1().ConfigMaps(td.namespace)
+
+	jobKind := taskObjectKind{
+		kind:     "Job",
+		selector: td.selector.Labels.String(),
+		client:   jobsClient,
+		names: listNames(jobsClient.List, func(l *k8sbatchv1.JobList) []string {
+			var rv []string
+			for _, job := range l.Items {
+				if td.selector.matchJob(job) {
+					rv = append(rv, job.Name)
+				}
+			}
+			return rv
+		}),
+	}
+	podKind := taskObjectKind{
+		kind:     "Pod",
+		selector: td.selector.podLabels().String(),
+		client:   podsClient,
+		names: listNames(podsClient.List, func(l *k8scorev1.PodList) []string {
+			return objectNames(l.Items, func(o k8scorev1.Pod) string { return o.Name })
+		}),
+	}
+	if td.selector.JobName != "" {
+		// a run scheduled by a CronJob shares the other objects with the other runs
+		return []taskObjectKind{jobKind, podKind}
+	}
+
+	// NOTE: controllers are deleted first to prevent jobs and pods from being recreated
+	return []taskObjectKind{
+		{
+			kind:     "CronJob",
+			selector: td.selector.Labels.String(),
+			client:   cronJobsClient,
+			names: listNames(cronJobsClient.List, func(l *k8sbatchv1.CronJobList) []string {
+				return objectNames(l.Items, func(o k8sbatchv1.CronJob) string { return o.Name })
+			}),
+		},
+		jobKind,
+		podKind,
+		{
+			kind:     "Secret",
+			selector: td.selector.Labels.String(),
+			client:   secretsClient,
+			names: listNames(secretsClient.List, func(l *k8scorev1.SecretList) []string {
+				return objectNames(l.Items, func(o k8scorev1.Secret) string { return o.Name })
+			}),
+		},
+		{
+			kind:     "ConfigMap",
+			selector: td.selector.Labels.String(),
+			client:   configMapsClient,
+			names: listNames(configMapsClient.List, func(l *k8scorev1.ConfigMapList) []string {
+				return objectNames(l.Items, func(o k8scorev1.ConfigMap) string { return o.Name })
+			}),
+		},
+	}
+}
+
+func objectNames[T any](items []T, name func(T) string) []string {
+	rv := make([]string, 0, len(items))
+	for _, item := range items {
+		rv = append(rv, name(item))
+	}
+	return rv
+}
+
+// Delete deletes the task objects and returns the deleted ones.
+func (td *taskDeleter) Delete(ctx context.Context) ([]k8scorev1.ObjectReference, error) {
+	var deleted []k8scorev1.ObjectReference
+
+	deleteOpts := k8smetav1.DeleteOptions{
+		PropagationPolicy: stdlib.Ptr(k8smetav1.DeletePropagationBackground),
+	}
+
+	for _, objectKind := range td.objectKinds() {
+		names, err := objectKind.names(ctx, k8smetav1.ListOptions{LabelSelector: objectKind.selector})
+		if err != nil {
+			return deleted, fmt.Errorf("failed to list %s objects: %w", objectKind.kind, err)
+		}
+
+		for _, name := range names {
+			err := objectKind.client.Delete(ctx, name, deleteOpts)
+			switch {
+			case err == nil:
+				deleted = append(deleted, k8scorev1.ObjectReference{
+					Kind:      objectKind.kind,
+					Namespace: td.namespace,
+					Name:      name,
+				})
+			case k8serrors.IsNotFound(err):
+			// already deleted
+			default:
+				return deleted, fmt.Errorf("failed to delete %s %q: %w", objectKind.kind, name, err)
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// WaitForRemoval blocks until all task objects are removed from the cluster.
+func (td *taskDeleter) WaitForRemoval(ctx context.Context, interval time.Duration) error {
+	objectKinds := td.objectKinds()
+
+	err := wait.PollUntilContextCancel(ctx, interval, true, func(ctx context.Context) (bool, error) {
+		for _, objectKind := range objectKinds {
+			names, err := objectKind.names(ctx, k8smetav1.ListOptions{LabelSelector: objectKind.selector})
+			if err != nil {
+				return false, fmt.Errorf("failed to list %s objects: %w", objectKind.kind, err)
+			}
+			if len(names) > 0 {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for task objects removal: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package task
+
+import (
+	"time"
+
+	"github.com/Azure/k6ctl/internal/kubelib"
+)
+
+type deleteTaskOption struct {
+	// Wait specifies whether to wait for the objects to be removed.
+	Wait bool
+	// WaitTimeout specifies the maximum duration to wait for the objects to be removed.
+	// Zero means no timeout.
+	WaitTimeout time.Duration
+	// WaitInterval specifies the interval for checking the remaining objects.
+	WaitInterval time.Duration
+	// KubeClientFactory provides the kubernetes client to use for the task.
+	// If not provided, createKubeClientFromKubeConfig is used.
+	// Unit test can provide a mock implementation.
+	KubeClientFactory kubelib.KubeClientFactory
+}
+
+func defaultDeleteTaskOption() *deleteTaskOption {
+	return &deleteTaskOption{
+		Wait:              false,
+		WaitInterval:      2 * time.Second,
+		KubeClientFactory: kubelib.CreateKubeClientFromKubeConfig,
+	}
+}
+
+// DeleteTaskOption configures the behavior of DeleteTask.
+type DeleteTaskOption interface {
+	apply(option *deleteTaskOption) error
+}
+
+type applyDeleteTaskOptionFunc func(option *deleteTaskOption) error
+
+func (f applyDeleteTaskOptionFunc) apply(option *deleteTaskOption) error {
+	return f(option)
+}
+
+// WithWait specifies whether to wait for the objects to be removed.
+// A zero timeout waits until the context is done.
+func WithWait(wait bool, timeout time.Duration) DeleteTaskOption {
+	return applyDeleteTaskOptionFunc(func(option *deleteTaskOption) error {
+		option.Wait = wait
+		option.WaitTimeout = timeout
+		return nil
+	})
+}
@@ -0,0 +1,75 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+func TestDeleteTask(t *testing.T) {
+	const namespace = "test"
+
+	objectMeta := func(name string, taskName string) k8smetav1.ObjectMeta {
+		return k8smetav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				labelKeyTaskName: taskName,
+			},
+		}
+	}
+
+	kubeClient := fake.NewSimpleClientset(
+		&k8sbatchv1.Job{ObjectMeta: objectMeta("k6ctl-job-test", "test")},
+		&k8scorev1.Pod{ObjectMeta: objectMeta("k6ctl-job-test-abcde", "test")},
+		&k8scorev1.Secret{ObjectMeta: objectMeta("k6ctl-configs-secret-test", "test")},
+		&k8scorev1.ConfigMap{ObjectMeta: objectMeta("k6ctl-scripts-config-test", "test")},
+		&k8scorev1.ConfigMap{ObjectMeta: objectMeta("k6ctl-scripts-config-other", "other")},
+	)
+
+	fakeTarget := &target.StaticTarget{
+		Kubeconfig: "/tmp/fake-kubeconfig",
+	}
+	withFakeClient := applyDeleteTaskOptionFunc(func(option *deleteTaskOption) error {
+		option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+			return kubeClient, nil
+		}
+		return nil
+	})
+
+	ctx := context.Background()
+
+	deleted, err := DeleteTask(
+		ctx, fakeTarget, TaskRef{Namespace: namespace, Name: "test"},
+		withFakeClient,
+		WithWait(true, 10*time.Second),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []k8scorev1.ObjectReference{
+		{Kind: "Job", Namespace: namespace, Name: "k6ctl-job-test"},
+		{Kind: "Pod", Namespace: namespace, Name: "k6ctl-job-test-abcde"},
+		{Kind: "Secret", Namespace: namespace, Name: "k6ctl-configs-secret-test"},
+		{Kind: "ConfigMap", Namespace: namespace, Name: "k6ctl-scripts-config-test"},
+	}, deleted)
+
+	configMaps, err := kubeClient.CoreV1().ConfigMaps(namespace).List(ctx, k8smetav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, configMaps.Items, 1)
+	assert.Equal(t, "k6ctl-scripts-config-other", configMaps.Items[0].Name)
+
+	deleted, err = DeleteTask(ctx, fakeTarget, TaskRef{Namespace: namespace, Name: "test"}, withFakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	_, err = DeleteTask(ctx, fakeTarget, TaskRef{Name: "test"}, withFakeClient)
+	assert.Error(t, err)
+}
@@ -0,0 +1,247 @@
+package task
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/k6ctl/internal/stdlib"
+)
+
+const (
+	// dependencyKindImport is a module imported or required by a script.
+	dependencyKindImport = "import"
+	// dependencyKindOpen is a file read by open() in a script.
+	dependencyKindOpen = "open"
+)
+
+// scriptDependency is a local file referenced by a mounted script.
+type scriptDependency struct {
+	scriptFile
+	// Kind is how the file is referenced, import or open.
+	Kind string
+	// ReferencedBy is the path of the script referencing the file, in the scripts volume.
+	ReferencedBy string
+	// Listed reports whether the file is already mounted by the files of the task config.
+	Listed bool
+}
+
+var (
+	// importPatterns match the module specifiers of static imports, re-exports, dynamic imports and require calls.
+	importPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\bimport\s+(?:[\w$*{}\s,]+?\s+from\s+)?["']([^"'\n]+)["']`),
+		regexp.MustCompile(`\bexport\s+[\w$*{}\s,]+?\s+from\s+["']([^"'\n]+)["']`),
+		regexp.MustCompile("\\b(?:import|require)\\s*\\(\\s*[\"'`]([^\"'`\\n]+)[\"'`]\\s*\\)"),
+	}
+	// openPattern matches the paths of the files read by open().
+	openPattern = regexp.MustCompile("\\bopen\\s*\\(\\s*[\"'`]([^\"'`\\n]+)[\"'`]")
+)
+
+// scriptExtensions are the extensions of the files scanned for dependencies.
+var scriptExtensions = map[string]bool{
+	".js":  true,
+	".mjs": true,
+	".cjs": true,
+	".ts":  true,
+}
+
+// maskScript blanks the comments and the contents of the string literals in the script, so the references
+// can be matched in code only. The offsets are kept, so the matched literals can be read from the source.
+func maskScript(src string) string {
+	b := []byte(src)
+	blank := func(from, to int, mask byte) {
+		for ; from < to; from++ {
+			if b[from] != '\n' {
+				b[from] = mask
+			}
+		}
+	}
+
+	for i := 0; i < len(src); {
+		switch c := src[i]; {
+		case c == '"' || c == '\'' || c == '`':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j, len(src))
+			blank(i+1, j, '_')
+			i = j + 1
+		case strings.HasPrefix(src[i:], "//"):
+			j := strings.IndexByte(src[i:], '\n')
+			if j < 0 {
+				j = len(src) - i
+			}
+			blank(i, i+j, ' ')
+			i += j
+		case strings.HasPrefix(src[i:], "/*"):
+			j := strings.Index(src[i+2:], "*/")
+			if j < 0 {
+				j = len(src) - i
+			} else {
+				j += 4
+			}
+			blank(i, i+j, ' ')
+			i += j
+		default:
+			i++
+		}
+	}
+	return string(b)
+}
+
+// scanScriptReferences returns the local files referenced by the script source, by kind.
+// Imports are local if they start with ./ or ../, while open() reads local files unless given a URL or absolute path.
+func scanScriptReferences(source string) map[string][]string {
+	masked := maskScript(source)
+	rv := map[string][]string{}
+
+	literals := func(pattern *regexp.Regexp) []string {
+		var literals []string
+		for _, m := range pattern.FindAllStringSubmatchIndex(masked, -1) {
+			literals = append(literals, source[m[2]:m[3]])
+		}
+		return literals
+	}
+
+	for _, pattern := range importPatterns {
+		for _, ref := range literals(pattern) {
+			if strings.HasPrefix(ref, "./") || strings.HasPrefix(ref, "../") {
+				rv[dependencyKindImport] = append(rv[dependencyKindImport], ref)
+			}
+		}
+	}
+	for _, ref := range literals(openPattern) {
+		if strings.Contains(ref, "://") || path.IsAbs(ref) {
+			continue
+		}
+		rv[dependencyKindOpen] = append(rv[dependencyKindOpen], ref)
+	}
+
+	return rv
+}
+
+// discoverScriptDependencies follows the local files referenced by the entry script transitively.
+// Files referenced from a script keep their path relative to the script in the scripts volume.
+// Referenced files missing locally, outside of the base directory or conflicting with a mounted file are
+// reported as warnings. The entry script is skipped if it's not mounted from the local files.
+func discoverScriptDependencies(
+	baseDir string,
+	files []scriptFile,
+	entry string,
+) ([]scriptDependency, []string, error) {
+	absBaseDir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid base dir %q: %w", baseDir, err)
+	}
+
+	mounted := map[string]scriptFile{}
+	for _, f := range files {
+		mounted[f.Path] = f
+	}
+	entryFile, ok := mounted[path.Clean(entry)]
+	if !ok {
+		return nil, nil, nil
+	}
+
+	var (
+		deps     []scriptDependency
+		warnings []string
+		visited  = map[string]bool{entryFile.Path: true}
+		queue    = []scriptFile{entryFile}
+	)
+	for len(queue) > 0 {
+		script := queue[0]
+		queue = queue[1:]
+
+		b, err := os.ReadFile(script.Source)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read script %q: %w", script.Path, err)
+		}
+		refs := scanScriptReferences(string(b))
+
+		for _, kind := range []string{dependencyKindImport, dependencyKindOpen} {
+			for _, ref := range refs[kind] {
+				volumePath := path.Join(path.Dir(script.Path), ref)
+				if visited[volumePath] {
+					continue
+				}
+				visited[volumePath] = true
+
+				source := filepath.Join(filepath.Dir(script.Source), filepath.FromSlash(ref))
+				dep := scriptDependency{
+					scriptFile:   scriptFile{Source: source, Path: volumePath},
+					Kind:         kind,
+					ReferencedBy: script.Path,
+				}
+
+				if existing, ok := mounted[volumePath]; ok {
+					if existing.Source != source {
+						warnings = append(warnings, fmt.Sprintf(
+							"%s references %q, which is mounted from %s instead of %s",
+							script.Path, ref, existing.Source, source,
+						))
+					}
+					dep.scriptFile = existing
+					dep.Listed = true
+				} else {
+					switch stat, err := os.Stat(source); {
+					case volumePath == ".." || strings.HasPrefix(volumePath, "../"):
+						warnings = append(warnings, fmt.Sprintf("%s references %q outside of the scripts volume", script.Path, ref))
+						continue
+					case !stdlib.IsChildPath(absBaseDir, source):
+						warnings = append(warnings, fmt.Sprintf("%s references %q outside of the base directory", script.Path, ref))
+						continue
+					case err != nil || !stat.Mode().IsRegular():
+						warnings = append(warnings, fmt.Sprintf("%s references %q, which is not found", script.Path, ref))
+						continue
+					}
+				}
+
+				deps = append(deps, dep)
+				if kind == dependencyKindImport && scriptExtensions[path.Ext(volumePath)] {
+					queue = append(queue, dep.scriptFile)
+				}
+			}
+		}
+	}
+
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].Path < deps[j].Path
+	})
+	return deps, warnings, nil
+}
+
+// printScriptDependencies prints the dependencies of the entry script.
+func printScriptDependencies(w io.Writer, entry string, deps []scriptDependency) error {
+	if len(deps) == 0 {
+		_, err := fmt.Fprintf(w, "No local dependencies found for %s\n", entry)
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "Local dependencies of %s:\n", entry); err != nil {
+		return err
+	}
+	for _, dep := range deps {
+		verb := "imported"
+		if dep.Kind == dependencyKindOpen {
+			verb = "opened"
+		}
+		status := "added"
+		if dep.Listed {
+			status = "listed in files"
+		}
+		if _, err := fmt.Fprintf(w, "  %s (%s by %s, %s)\n", dep.Path, verb, dep.ReferencedBy, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
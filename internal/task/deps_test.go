@@ -0,0 +1,138 @@
+package task
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanScriptReferences(t *testing.T) {
+	refs := scanScriptReferences(`
+import http from 'k6/http';
+import { check } from "k6";
+import "./setup.js";
+import * as lib from './lib/index.js';
+import {
+  a,
+  b as c,
+} from "../shared/ab.js";
+export { x } from './x.js';
+const dyn = await import('./dynamic.js');
+const cjs = require("./legacy.js");
+// import { old } from './old.js';
+/* const data = open('./old.json'); */
+const url = "http://example.com/open('./not-a-call.json')";
+const users = JSON.parse(open('./data/users.json'));
+const bin = open(` + "`fixtures/image.png`" + `, 'b');
+const remote = open('https://example.com/data.json');
+`)
+
+	assert.ElementsMatch(t, []string{
+		"./setup.js",
+		"./lib/index.js",
+		"../shared/ab.js",
+		"./x.js",
+		"./dynamic.js",
+		"./legacy.js",
+	}, refs[dependencyKindImport])
+	assert.ElementsMatch(t, []string{
+		"./data/users.json",
+		"fixtures/image.png",
+	}, refs[dependencyKindOpen])
+}
+
+func TestDiscoverScriptDependencies(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(p string, content string) {
+		abs := filepath.Join(dir, filepath.FromSlash(p))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(abs), 0o755))
+		assert.NoError(t, os.WriteFile(abs, []byte(content), 0o644))
+	}
+	writeFile("tests/run.js", `
+import { get } from './lib/client.js';
+import { helper } from './lib/helper.js';
+import { missing } from './lib/missing.js';
+const users = open('./data/users.json');
+`)
+	writeFile("tests/lib/client.js", `
+import { fmt } from './fmt.js';
+import { get } from './client.js';
+const cfg = open('../data/config.json');
+`)
+	writeFile("tests/lib/fmt.js", `import { x } from '../../../outside.js';`)
+	writeFile("tests/lib/helper.js", `import { fmt } from './fmt.js';`)
+	writeFile("tests/data/users.json", `[]`)
+	writeFile("tests/data/config.json", `{}`)
+	writeFile("helper.js", `export function helper() {}`)
+
+	files, err := resolveFileMounts(dir, []FileMount{
+		{Source: "tests/run.js", Dest: "run.js"},
+		{Source: "tests/lib/helper.js", Dest: "lib/helper.js"},
+	})
+	assert.NoError(t, err)
+
+	deps, warnings, err := discoverScriptDependencies(dir, files, "./run.js")
+	assert.NoError(t, err)
+	assert.Equal(t, []scriptDependency{
+		{
+			scriptFile:   scriptFile{Source: filepath.Join(dir, "tests", "data", "config.json"), Path: "data/config.json"},
+			Kind:         dependencyKindOpen,
+			ReferencedBy: "lib/client.js",
+		},
+		{
+			scriptFile:   scriptFile{Source: filepath.Join(dir, "tests", "data", "users.json"), Path: "data/users.json"},
+			Kind:         dependencyKindOpen,
+			ReferencedBy: "run.js",
+		},
+		{
+			scriptFile:   scriptFile{Source: filepath.Join(dir, "tests", "lib", "client.js"), Path: "lib/client.js"},
+			Kind:         dependencyKindImport,
+			ReferencedBy: "run.js",
+		},
+		{
+			scriptFile:   scriptFile{Source: filepath.Join(dir, "tests", "lib", "fmt.js"), Path: "lib/fmt.js"},
+			Kind:         dependencyKindImport,
+			ReferencedBy: "lib/client.js",
+		},
+		{
+			scriptFile:   scriptFile{Source: filepath.Join(dir, "tests", "lib", "helper.js"), Path: "lib/helper.js"},
+			Kind:         dependencyKindImport,
+			ReferencedBy: "run.js",
+			Listed:       true,
+		},
+	}, deps)
+	assert.Equal(t, []string{
+		`run.js references "./lib/missing.js", which is not found`,
+		`lib/fmt.js references "../../../outside.js" outside of the scripts volume`,
+	}, warnings)
+
+	var out bytes.Buffer
+	assert.NoError(t, printScriptDependencies(&out, "run.js", deps[3:]))
+	assert.Equal(t, `Local dependencies of run.js:
+  lib/fmt.js (imported by lib/client.js, added)
+  lib/helper.js (imported by run.js, listed in files)
+`, out.String())
+
+	t.Run("script not mounted", func(t *testing.T) {
+		deps, warnings, err := discoverScriptDependencies(dir, files, "other.js")
+		assert.NoError(t, err)
+		assert.Empty(t, deps)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("conflicting mount", func(t *testing.T) {
+		files, err := resolveFileMounts(dir, []FileMount{
+			{Source: "tests/run.js", Dest: "run.js"},
+			{Source: "helper.js", Dest: "lib/helper.js"},
+		})
+		assert.NoError(t, err)
+
+		_, warnings, err := discoverScriptDependencies(dir, files, "run.js")
+		assert.NoError(t, err)
+		assert.Contains(t, warnings, `run.js references "./lib/helper.js", which is mounted from `+
+			filepath.Join(dir, "helper.js")+` instead of `+filepath.Join(dir, "tests", "lib", "helper.js"))
+	})
+}
@@ -0,0 +1,94 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+)
+
+// k6ExitCodeThresholdsFailed is the exit code of k6 when thresholds are crossed.
+// ref: https://github.com/grafana/k6/blob/master/errext/exitcodes/codes.go
+const k6ExitCodeThresholdsFailed = 99
+
+// FailedPod describes a task pod which did not succeed.
+type FailedPod struct {
+	Name string
+	// ExitCode is the exit code of the k6 runner container. It's nil if the container did not terminate.
+	ExitCode *int32
+	// Reason is the termination reason of the k6 runner container or the pod.
+	Reason string
+}
+
+func (p FailedPod) String() string {
+	var details []string
+	if p.ExitCode != nil {
+		details = append(details, fmt.Sprintf("exit code %d", *p.ExitCode))
+	}
+	if p.Reason != "" {
+		details = append(details, p.Reason)
+	}
+	if len(details) == 0 {
+		return p.Name
+	}
+	return fmt.Sprintf("%s (%s)", p.Name, strings.Join(details, ", "))
+}
+
+func failedPodNames(pods []FailedPod) string {
+	names := make([]string, 0, len(pods))
+	for _, p := range pods {
+		names = append(names, p.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// ThresholdsFailedError is returned when k6 reports crossed thresholds in the task pods,
+// or when the thresholds evaluated across all instances are crossed.
+type ThresholdsFailedError struct {
+	Pods []FailedPod
+	// Thresholds are the failed thresholds evaluated across all instances.
+	Thresholds []ThresholdResult
+}
+
+func (e *ThresholdsFailedError) Error() string {
+	if len(e.Thresholds) > 0 {
+		names := make([]string, 0, len(e.Thresholds))
+		for _, r := range e.Thresholds {
+			names = append(names, r.String())
+		}
+		return fmt.Sprintf("thresholds failed across all instances: %s", strings.Join(names, ", "))
+	}
+	return fmt.Sprintf("thresholds failed in %d pod(s): %s", len(e.Pods), failedPodNames(e.Pods))
+}
+
+// ExitCode returns the k6 exit code for crossed thresholds.
+func (e *ThresholdsFailedError) ExitCode() int {
+	return k6ExitCodeThresholdsFailed
+}
+
+// TaskFailedError is returned when any task pod failed.
+type TaskFailedError struct {
+	Job  string
+	Pods []FailedPod
+}
+
+func (e *TaskFailedError) Error() string {
+	if len(e.Pods) == 0 {
+		return fmt.Sprintf("job %q failed", e.Job)
+	}
+	return fmt.Sprintf("job %q failed in %d pod(s): %s", e.Job, len(e.Pods), failedPodNames(e.Pods))
+}
+
+// ExitCode returns the k6 exit code if all failed pods exited with the same code.
+// Otherwise, 1 is returned.
+func (e *TaskFailedError) ExitCode() int {
+	rv := 1
+	for idx, p := range e.Pods {
+		if p.ExitCode == nil || *p.ExitCode == 0 {
+			return 1
+		}
+		if idx > 0 && int(*p.ExitCode) != rv {
+			return 1
+		}
+		rv = int(*p.ExitCode)
+	}
+	return rv
+}
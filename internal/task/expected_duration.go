@@ -0,0 +1,45 @@
+package task
+
+import (
+	"fmt"
+	"time"
+)
+
+// runStartupAllowance is the time allowed for scheduling the instances and pulling the images of a run.
+const runStartupAllowance = 10 * time.Minute
+
+// ExpectedRunDuration returns how long a run of the task is expected to take from resolving its configs:
+// k6.expectedDuration plus the time for starting the instances, which covers the files upload timeout,
+// the start barrier timeout and a fixed allowance for scheduling and image pulls.
+// Zero is returned if k6.expectedDuration is not set.
+func ExpectedRunDuration(k6 K6, options ...RunTaskOption) (time.Duration, error) {
+	if k6.ExpectedDuration == "" {
+		return 0, nil
+	}
+	rv, err := time.ParseDuration(k6.ExpectedDuration)
+	if err != nil || rv <= 0 {
+		return 0, fmt.Errorf("invalid k6.expectedDuration %q", k6.ExpectedDuration)
+	}
+
+	opt := defaultRunTaskOption()
+	for _, o := range options {
+		if err := o.apply(opt); err != nil {
+			return 0, err
+		}
+	}
+
+	assets, err := resolveAssets(k6)
+	if err != nil {
+		return 0, err
+	}
+	startBarrier, startBarrierTimeout, err := resolveStartBarrier(opt, k6)
+	if err != nil {
+		return 0, err
+	}
+
+	rv += runStartupAllowance + assets.UploadTimeout
+	if startBarrier {
+		rv += startBarrierTimeout
+	}
+	return rv, nil
+}
@@ -0,0 +1,58 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpectedRunDuration(t *testing.T) {
+	cases := []struct {
+		name      string
+		k6        K6
+		options   []RunTaskOption
+		expected  time.Duration
+		expectErr bool
+	}{
+		{
+			name: "not set",
+		},
+		{
+			name:     "with defaults",
+			k6:       K6{ExpectedDuration: "30m"},
+			expected: 30*time.Minute + runStartupAllowance + defaultAssetsUploadTimeout,
+		},
+		{
+			name: "with upload timeout",
+			k6: K6{
+				ExpectedDuration: "30m",
+				Assets:           K6Assets{UploadTimeout: "15m"},
+			},
+			expected: 30*time.Minute + runStartupAllowance + 15*time.Minute,
+		},
+		{
+			name:     "with start barrier",
+			k6:       K6{ExpectedDuration: "30m"},
+			options:  []RunTaskOption{WithStartBarrier(true, 2*time.Minute)},
+			expected: 30*time.Minute + runStartupAllowance + defaultAssetsUploadTimeout + 2*time.Minute,
+		},
+		{
+			name:      "invalid",
+			k6:        K6{ExpectedDuration: "soon"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ExpectedRunDuration(tc.k6, tc.options...)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package task
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	k8scorev1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/k6ctl/internal/stdlib"
+)
+
+// scriptFile is a local file mounted to the scripts volume.
+type scriptFile struct {
+	// Source is the absolute path of the local file.
+	Source string
+	// Path is the slash separated path of the file in the scripts volume.
+	Path string
+	// Key is the key of the file in the scripts config map, see assignScriptFileKeys.
+	Key string
+	// Binary forces the file to be mounted as binary data.
+	Binary bool
+	// Size is the size of the file in bytes, see planScriptsLayout.
+	Size int64
+	// Content overrides the content of the local file if not nil, e.g. for scripts with rewritten imports.
+	Content []byte
+}
+
+// read returns the content of the file.
+func (f scriptFile) read() ([]byte, error) {
+	if f.Content != nil {
+		return f.Content, nil
+	}
+	b, err := os.ReadFile(f.Source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source file %q: %w", f.Source, err)
+	}
+	return b, nil
+}
+
+// size returns the size of the content of the file.
+func (f scriptFile) size() (int64, error) {
+	if f.Content != nil {
+		return int64(len(f.Content)), nil
+	}
+	stat, err := os.Stat(f.Source)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read source file %q: %w", f.Source, err)
+	}
+	return stat.Size(), nil
+}
+
+// hasGlobMeta reports whether the path contains glob meta characters.
+func hasGlobMeta(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// validatePathPattern checks the syntax of the slash separated glob pattern.
+func validatePathPattern(pattern string) error {
+	for _, segment := range strings.Split(pattern, "/") {
+		if segment == "**" {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchPathPattern reports whether the slash separated name matches the glob pattern.
+// ** matches zero or more path segments, other segments are matched by path.Match.
+func matchPathPattern(pattern string, name string) bool {
+	return matchPathSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchPathSegments(pattern []string, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchPathSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// isExcluded reports whether the slash separated path relative to the source root matches any exclude pattern.
+// Patterns without slash match the base name at any depth.
+func isExcluded(excludes []string, rel string) bool {
+	for _, pattern := range excludes {
+		pattern = strings.TrimSuffix(pattern, "/")
+		if !strings.Contains(pattern, "/") && matchPathPattern(pattern, path.Base(rel)) {
+			return true
+		}
+		if matchPathPattern(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitGlobPattern splits the slash separated glob into the static directory prefix and the pattern.
+func splitGlobPattern(glob string) (string, string) {
+	segments := strings.Split(glob, "/")
+	for idx, segment := range segments {
+		if hasGlobMeta(segment) {
+			return path.Join(segments[:idx]...), path.Join(segments[idx:]...)
+		}
+	}
+	return glob, ""
+}
+
+// validateDestPath checks the dest is a relative path staying inside of the scripts volume.
+func validateDestPath(dest string) error {
+	switch {
+	case path.IsAbs(dest):
+		return fmt.Errorf("dest %q must be a relative path", dest)
+	case dest == ".." || strings.HasPrefix(dest, "../") || strings.Contains(dest, "/../") || strings.HasSuffix(dest, "/.."):
+		return fmt.Errorf("dest %q must not contain ..", dest)
+	}
+	return nil
+}
+
+// walkSourceFiles lists the regular files under the root directory by their slash separated paths
+// relative to the root, skipping the excluded files and directories.
+// If pattern is not empty, only the files matching it are listed.
+func walkSourceFiles(root string, pattern string, excludes []string) ([]string, error) {
+	var rv []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if isExcluded(excludes, rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if pattern != "" && !matchPathPattern(pattern, rel) {
+			return nil
+		}
+		// symlinks to regular files are mounted with the target content
+		if !d.Type().IsRegular() {
+			stat, err := os.Stat(p)
+			if err != nil || !stat.Mode().IsRegular() {
+				return nil
+			}
+		}
+
+		rv = append(rv, rel)
+		return nil
+	})
+	return rv, err
+}
+
+// resolveFileMount expands the file mount to the files it mounts.
+//
+// The source can be a file, a directory or a glob like lib/**/*.js relative to baseDir.
+// Files under a directory or matching a glob keep their paths relative to the directory or the glob's
+// leading directory, under the dest prefix. The dest defaults to the source path.
+func resolveFileMount(baseDir string, mount FileMount) ([]scriptFile, error) {
+	source := path.Clean(filepath.ToSlash(mount.Source))
+	if mount.Dest != "" {
+		if err := validateDestPath(mount.Dest); err != nil {
+			return nil, err
+		}
+	}
+	for _, exclude := range mount.Exclude {
+		if err := validatePathPattern(exclude); err != nil {
+			return nil, fmt.Errorf("invalid exclude: %w", err)
+		}
+	}
+
+	absBaseDir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base dir %q: %w", baseDir, err)
+	}
+
+	root, pattern := source, ""
+	if hasGlobMeta(source) {
+		if err := validatePathPattern(source); err != nil {
+			return nil, err
+		}
+		root, pattern = splitGlobPattern(source)
+		if root == "" {
+			root = "."
+		}
+	}
+
+	absRoot := filepath.Join(absBaseDir, filepath.FromSlash(root))
+	if !stdlib.IsChildPath(absBaseDir, absRoot) {
+		return nil, fmt.Errorf("source %q is outside of the base directory %q", mount.Source, absBaseDir)
+	}
+
+	stat, err := os.Stat(absRoot)
+	if err != nil {
+		return nil, fmt.Errorf("source %q not found", mount.Source)
+	}
+
+	destPrefix := mount.Dest
+	if destPrefix == "" {
+		destPrefix = root
+	}
+
+	if !stat.IsDir() {
+		if pattern != "" {
+			return nil, fmt.Errorf("source %q not found", mount.Source)
+		}
+		return []scriptFile{{Source: absRoot, Path: path.Clean(destPrefix), Binary: mount.Binary}}, nil
+	}
+
+	rels, err := walkSourceFiles(absRoot, pattern, mount.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("list files of %q: %w", mount.Source, err)
+	}
+	if len(rels) == 0 {
+		return nil, fmt.Errorf("no files found for source %q", mount.Source)
+	}
+
+	rv := make([]scriptFile, 0, len(rels))
+	for _, rel := range rels {
+		rv = append(rv, scriptFile{
+			Source: filepath.Join(absRoot, filepath.FromSlash(rel)),
+			Path:   path.Join(destPrefix, rel),
+			Binary: mount.Binary,
+		})
+	}
+	return rv, nil
+}
+
+// resolveFileMounts expands the file mounts to the files they mount, sorted by path.
+// Mounting different files to the same path is rejected.
+func resolveFileMounts(baseDir string, mounts []FileMount) ([]scriptFile, error) {
+	var rv []scriptFile
+	mountedBy := map[string]int{}
+	for idx, mount := range mounts {
+		files, err := resolveFileMount(baseDir, mount)
+		if err != nil {
+			return nil, fmt.Errorf("files[%d]: %w", idx, err)
+		}
+		for _, f := range files {
+			if first, ok := mountedBy[f.Path]; ok {
+				return nil, fmt.Errorf("files[%d]: %q is already mounted by files[%d]", idx, f.Path, first)
+			}
+			mountedBy[f.Path] = idx
+			rv = append(rv, f)
+		}
+	}
+
+	sort.Slice(rv, func(i, j int) bool {
+		return rv[i].Path < rv[j].Path
+	})
+	return rv, nil
+}
+
+// configMapKeyInvalidChars matches the characters not allowed in ConfigMap keys.
+var configMapKeyInvalidChars = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// maxConfigMapKeyLength is the max length of ConfigMap keys, leaving room for the dedup suffix.
+const maxConfigMapKeyLength = 240
+
+// assignScriptFileKeys assigns unique ConfigMap keys to the files. ConfigMap keys can't contain slashes,
+// so the files are mounted to their paths with the items of the ConfigMap volume.
+// Files in the root of the volume are keyed by their names.
+func assignScriptFileKeys(files []scriptFile) {
+	used := map[string]bool{}
+	for idx := range files {
+		key := strings.ReplaceAll(files[idx].Path, "/", "__")
+		key = configMapKeyInvalidChars.ReplaceAllString(key, "_")
+		if len(key) > maxConfigMapKeyLength {
+			key = key[len(key)-maxConfigMapKeyLength:]
+		}
+		if key == "." || key == ".." {
+			key = "_" + key
+		}
+
+		candidate := key
+		for n := 2; used[candidate]; n++ {
+			candidate = fmt.Sprintf("%s-%d", key, n)
+		}
+		used[candidate] = true
+		files[idx].Key = candidate
+	}
+}
+
+// scriptsVolumeItems maps the config map keys to the file paths in the scripts volume.
+// It's nil if all files are keyed by their paths.
+func scriptsVolumeItems(files []scriptFile) []k8scorev1.KeyToPath {
+	var needed bool
+	items := make([]k8scorev1.KeyToPath, 0, len(files))
+	for _, f := range files {
+		needed = needed || f.Key != f.Path
+		items = append(items, k8scorev1.KeyToPath{Key: f.Key, Path: f.Path})
+	}
+	if !needed {
+		return nil
+	}
+	return items
+}
@@ -0,0 +1,268 @@
+package task
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/k6ctl/internal/config"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+// writeTestFiles writes the files with their paths as content under a temp directory.
+func writeTestFiles(t *testing.T, paths ...string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, p := range paths {
+		abs := filepath.Join(dir, filepath.FromSlash(p))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(abs), 0o755))
+		assert.NoError(t, os.WriteFile(abs, []byte(p), 0o644))
+	}
+	return dir
+}
+
+func scriptFilePaths(files []scriptFile) []string {
+	var rv []string
+	for _, f := range files {
+		rv = append(rv, f.Path)
+	}
+	return rv
+}
+
+func TestMatchPathPattern(t *testing.T) {
+	cases := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"*.js", "run.js", true},
+		{"*.js", "lib/run.js", false},
+		{"**/*.js", "run.js", true},
+		{"**/*.js", "lib/a/run.js", true},
+		{"lib/**", "lib/a/run.js", true},
+		{"lib/**/*.js", "lib/run.js", true},
+		{"lib/**/*.js", "lib/a/b/run.js", true},
+		{"lib/**/*.js", "lib/a/b/run.ts", false},
+		{"lib/*/run.js", "lib/a/b/run.js", false},
+		{"[ab].js", "a.js", true},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.match, matchPathPattern(c.pattern, c.name), "%s ~ %s", c.pattern, c.name)
+	}
+}
+
+func TestResolveFileMount(t *testing.T) {
+	dir := writeTestFiles(
+		t,
+		"run.js",
+		"lib/http.js",
+		"lib/http_test.js",
+		"lib/util/strings.js",
+		"lib/util/README.md",
+		"lib/node_modules/dep/index.js",
+		"protos/api.proto",
+		"protos/v1/service.proto",
+	)
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "empty"), 0o755))
+
+	t.Run("file", func(t *testing.T) {
+		files, err := resolveFileMount(dir, FileMount{Source: "run.js", Dest: "main.js"})
+		assert.NoError(t, err)
+		assert.Equal(t, []scriptFile{{Source: filepath.Join(dir, "run.js"), Path: "main.js"}}, files)
+
+		files, err = resolveFileMount(dir, FileMount{Source: "./lib/http.js"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"lib/http.js"}, scriptFilePaths(files))
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		files, err := resolveFileMount(dir, FileMount{Source: "protos/"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"protos/api.proto", "protos/v1/service.proto"}, scriptFilePaths(files))
+		assert.Equal(t, filepath.Join(dir, "protos", "v1", "service.proto"), files[1].Source)
+
+		files, err = resolveFileMount(dir, FileMount{Source: "protos", Dest: "defs/grpc"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"defs/grpc/api.proto", "defs/grpc/v1/service.proto"}, scriptFilePaths(files))
+	})
+
+	t.Run("glob", func(t *testing.T) {
+		files, err := resolveFileMount(dir, FileMount{Source: "lib/**/*.js"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"lib/http.js",
+			"lib/http_test.js",
+			"lib/node_modules/dep/index.js",
+			"lib/util/strings.js",
+		}, scriptFilePaths(files))
+
+		files, err = resolveFileMount(dir, FileMount{
+			Source:  "lib/**/*.js",
+			Dest:    "modules",
+			Exclude: []string{"*_test.js", "node_modules/"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"modules/http.js", "modules/util/strings.js"}, scriptFilePaths(files))
+
+		files, err = resolveFileMount(dir, FileMount{Source: "**/*.proto", Exclude: []string{"protos/v1/**"}})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"protos/api.proto"}, scriptFilePaths(files))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := resolveFileMount(dir, FileMount{Source: "../run.js"})
+		assert.ErrorContains(t, err, "outside of the base directory")
+
+		_, err = resolveFileMount(dir, FileMount{Source: "missing.js"})
+		assert.ErrorContains(t, err, `source "missing.js" not found`)
+
+		_, err = resolveFileMount(dir, FileMount{Source: "empty"})
+		assert.ErrorContains(t, err, `no files found for source "empty"`)
+
+		_, err = resolveFileMount(dir, FileMount{Source: "lib/*.ts"})
+		assert.ErrorContains(t, err, `no files found for source "lib/*.ts"`)
+
+		_, err = resolveFileMount(dir, FileMount{Source: "run.js", Dest: "../run.js"})
+		assert.ErrorContains(t, err, `dest "../run.js" must not contain ..`)
+
+		_, err = resolveFileMount(dir, FileMount{Source: "lib", Exclude: []string{"[a"}})
+		assert.ErrorContains(t, err, `invalid pattern "[a"`)
+	})
+}
+
+func TestResolveFileMounts(t *testing.T) {
+	dir := writeTestFiles(t, "run.js", "lib/http.js")
+
+	files, err := resolveFileMounts(dir, []FileMount{
+		{Source: "run.js"},
+		{Source: "lib"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"lib/http.js", "run.js"}, scriptFilePaths(files))
+
+	_, err = resolveFileMounts(dir, []FileMount{
+		{Source: "lib"},
+		{Source: "run.js", Dest: "lib/http.js"},
+	})
+	assert.EqualError(t, err, `files[1]: "lib/http.js" is already mounted by files[0]`)
+}
+
+func TestAssignScriptFileKeys(t *testing.T) {
+	files := []scriptFile{
+		{Path: "lib/util.js"},
+		{Path: "lib__util.js"},
+		{Path: "protos/my api.proto"},
+		{Path: "run.js"},
+	}
+	assignScriptFileKeys(files)
+
+	assert.Equal(t, "lib__util.js", files[0].Key)
+	assert.Equal(t, "lib__util.js-2", files[1].Key)
+	assert.Equal(t, "protos__my_api.proto", files[2].Key)
+	assert.Equal(t, "run.js", files[3].Key)
+
+	assert.Equal(t, []k8scorev1.KeyToPath{
+		{Key: "lib__util.js", Path: "lib/util.js"},
+		{Key: "lib__util.js-2", Path: "lib__util.js"},
+		{Key: "protos__my_api.proto", Path: "protos/my api.proto"},
+		{Key: "run.js", Path: "run.js"},
+	}, scriptsVolumeItems(files))
+	assert.Nil(t, scriptsVolumeItems(files[3:]))
+}
+
+func TestRunTask_DirectoryFiles(t *testing.T) {
+	const namespace = "test"
+	dir := writeTestFiles(t, "run.js", "lib/http.js", "lib/util/strings.js")
+
+	ctx := context.Background()
+	kubeClient := fake.NewSimpleClientset()
+	err := RunTask(
+		ctx,
+		&target.StaticTarget{Kubeconfig: "/tmp/fake-kubeconfig"},
+		config.NewRegistry().GetByName,
+		&Schema{
+			Name: "test",
+			Files: []FileMount{
+				{Source: "run.js"},
+				{Source: "lib/**/*.js"},
+			},
+			K6: K6{Namespace: namespace},
+		},
+		dir,
+		"run.js",
+		applyRunTaskOptionFunc(func(option *runTaskOption) error {
+			option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+				return kubeClient, nil
+			}
+			return nil
+		}),
+		WithRunID("run1"),
+		WithFollowLogs(false),
+		WithWaitForCompletion(false),
+	)
+	assert.NoError(t, err)
+
+	configMaps, err := kubeClient.CoreV1().ConfigMaps(namespace).List(ctx, k8smetav1.ListOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, configMaps.Items, 1) {
+		assert.Equal(t, map[string]string{
+			"lib__http.js":          "lib/http.js",
+			"lib__util__strings.js": "lib/util/strings.js",
+			"run.js":                "run.js",
+		}, configMaps.Items[0].Data)
+	}
+
+	jobs, err := kubeClient.BatchV1().Jobs(namespace).List(ctx, k8smetav1.ListOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, jobs.Items, 1) {
+		volumes := jobs.Items[0].Spec.Template.Spec.Volumes
+		if assert.Len(t, volumes, 1) {
+			assert.Equal(t, []k8scorev1.KeyToPath{
+				{Key: "lib__http.js", Path: "lib/http.js"},
+				{Key: "lib__util__strings.js", Path: "lib/util/strings.js"},
+				{Key: "run.js", Path: "run.js"},
+			}, volumes[0].ConfigMap.Items)
+		}
+	}
+}
+
+func TestBuildScriptsConfigMapObject_Binary(t *testing.T) {
+	dir := t.TempDir()
+	gzipped := []byte{0x1f, 0x8b, 0x08, 0x00, 0xff, 0xfe}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "run.js"), []byte("export default function () {}"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "fixture.gz"), gzipped, 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "api.pb"), []byte("descriptor"), 0o644))
+
+	tr := &taskRunner{
+		runID:         "run1",
+		sourceBaseDir: dir,
+		script:        "run.js",
+		taskConfig: &Schema{
+			Name: "test",
+			Files: []FileMount{
+				{Source: "run.js"},
+				{Source: "fixture.gz"},
+				{Source: "api.pb", Binary: true},
+			},
+			K6: K6{Namespace: "test"},
+		},
+	}
+	files, err := tr.resolveScriptFiles(context.Background())
+	assert.NoError(t, err)
+
+	configMap, err := tr.buildScriptsConfigMapObject(context.Background(), 0, files)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"run.js": "export default function () {}"}, configMap.Data)
+	assert.Equal(t, map[string][]byte{
+		"api.pb":     []byte("descriptor"),
+		"fixture.gz": gzipped,
+	}, configMap.BinaryData)
+}
@@ -0,0 +1,41 @@
+package task
+
+import (
+	"sort"
+
+	"github.com/Azure/k6ctl/internal/jsonschema"
+)
+
+// JSONSchema generates the JSON Schema of the task config from the Schema type.
+// paramsSchemas maps the config provider names to the schemas of their params, which are applied
+// to the params of the configs using the provider.
+func JSONSchema(paramsSchemas map[string]*jsonschema.Schema) *jsonschema.Schema {
+	rv := jsonschema.For[Schema]("json")
+	rv.Schema = jsonschema.Draft
+	rv.Title = "k6ctl task config"
+
+	names := make([]string, 0, len(paramsSchemas))
+	for name := range paramsSchemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	provider := rv.Properties["configs"].Items.Properties["provider"]
+	for _, name := range names {
+		provider.AllOf = append(provider.AllOf, &jsonschema.Schema{
+			If: &jsonschema.Schema{
+				Properties: map[string]*jsonschema.Schema{
+					"name": {Const: name},
+				},
+				Required: []string{"name"},
+			},
+			Then: &jsonschema.Schema{
+				Properties: map[string]*jsonschema.Schema{
+					"params": paramsSchemas[name],
+				},
+			},
+		})
+	}
+
+	return rv
+}
@@ -0,0 +1,61 @@
+package task
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Azure/k6ctl/internal/jsonschema"
+)
+
+// assertSchemaMatchesType asserts every json field of the struct type is described by the schema.
+func assertSchemaMatchesType(t *testing.T, path string, s *jsonschema.Schema, typ reflect.Type) {
+	t.Helper()
+
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+		if s.Items != nil {
+			s = s.Items
+		}
+	}
+	if typ.Kind() != reflect.Struct {
+		return
+	}
+
+	if !assert.NotNil(t, s.Properties, path) {
+		return
+	}
+	assert.Len(t, s.Properties, typ.NumField(), path)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		prop, ok := s.Properties[name]
+		if assert.True(t, ok, "%s.%s is not in the schema", path, name) {
+			assertSchemaMatchesType(t, path+"."+name, prop, field.Type)
+		}
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	paramsSchema := &jsonschema.Schema{Type: "object"}
+	s := JSONSchema(map[string]*jsonschema.Schema{
+		"parameter":   paramsSchema,
+		"foo/secrets": paramsSchema,
+	})
+
+	assert.Equal(t, jsonschema.Draft, s.Schema)
+	assertSchemaMatchesType(t, "$", s, reflect.TypeOf(Schema{}))
+
+	assert.Equal(t, []string{"name"}, s.Required)
+	assert.Equal(t, []string{"image", "namespace"}, s.Properties["k6"].Required)
+	assert.Equal(t, []any{"replicate", "segments"}, s.Properties["k6"].Properties["distribution"].Enum)
+
+	provider := s.Properties["configs"].Items.Properties["provider"]
+	if assert.Len(t, provider.AllOf, 2) {
+		assert.Equal(t, "foo/secrets", provider.AllOf[0].If.Properties["name"].Const)
+		assert.Equal(t, "parameter", provider.AllOf[1].If.Properties["name"].Const)
+		assert.Same(t, paramsSchema, provider.AllOf[1].Then.Properties["params"])
+	}
+}
@@ -3,27 +3,26 @@ package task
 import (
 	"io"
 	"os"
-
-	"github.com/goccy/go-yaml"
 )
 
+// LoadSchema loads the task config strictly: unknown fields and duplicate keys are rejected.
+// Decoding problems are reported as SchemaErrors with their positions.
 func LoadSchema(s io.Reader) (*Schema, error) {
-	dec := yaml.NewDecoder(s, yaml.UseJSONUnmarshaler())
-
-	rv := new(Schema)
-	if err := dec.Decode(rv); err != nil {
+	source, err := io.ReadAll(s)
+	if err != nil {
 		return nil, err
 	}
 
-	return rv, nil
+	return decodeSchema("", source)
 }
 
+// LoadSchemaFromFile loads the task config file strictly.
+// Use ValidateSchemaFile to validate the semantics of the task config too.
 func LoadSchemaFromFile(path string) (*Schema, error) {
-	f, err := os.Open(path)
+	source, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
 
-	return LoadSchema(f)
+	return decodeSchema(path, source)
 }
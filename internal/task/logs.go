@@ -0,0 +1,92 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/Azure/k6ctl/internal/kubelib"
+	"github.com/Azure/k6ctl/internal/stdlib"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+// TaskLogs reads the logs of the k6 runner pods of the task.
+// It can be used to re-attach to a running task or replay the logs of a finished one.
+func TaskLogs(
+	ctx context.Context,
+	target target.Target,
+	taskRef TaskRef,
+	options ...TaskLogsOption,
+) error {
+	opt := defaultTaskLogsOption()
+	for _, o := range options {
+		if err := o.apply(opt); err != nil {
+			return err
+		}
+	}
+
+	if err := taskRef.validate(); err != nil {
+		return err
+	}
+	namespace := taskRef.Namespace
+
+	kubeconfig, ok := target.GetKubeconfig()
+	if !ok {
+		return fmt.Errorf("target does not have kubeconfig")
+	}
+	kubeClient, err := opt.KubeClientFactory(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	selector, err := resolveRunSelector(ctx, kubeClient, taskRef)
+	if err != nil {
+		return err
+	}
+
+	addPrefix := opt.AddPrefix
+	if !addPrefix && len(opt.PodNames) != 1 {
+		jobs, err := kubeClient.BatchV1().Jobs(namespace).List(ctx, k8smetav1.ListOptions{
+			LabelSelector: selector.Labels.String(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list jobs: %w", err)
+		}
+		jobs.Items = slices.DeleteFunc(jobs.Items, func(job k8sbatchv1.Job) bool {
+			return !selector.matchJob(job)
+		})
+		if len(jobs.Items) > 1 {
+			addPrefix = true
+		}
+		for _, job := range jobs.Items {
+			if stdlib.ValOrZero(job.Spec.Parallelism) > 1 {
+				// there might be multiple pods, add prefix to distinguish them
+				addPrefix = true
+			}
+		}
+	}
+
+	params := &kubelib.FollowLogsParams{
+		Namespace: namespace,
+		Selector:  labels.SelectorFromSet(selector.podLabels()),
+		Container: containerNameRunner,
+		AddPrefix: addPrefix,
+		NoFollow:  !opt.Follow,
+		PodNames:  opt.PodNames,
+		SkipLine:  isSummaryLogLine,
+		Output:    opt.Output,
+	}
+	if opt.Since > 0 {
+		params.SinceSeconds = stdlib.Ptr(int64(math.Ceil(opt.Since.Seconds())))
+	}
+	if opt.Tail >= 0 {
+		params.TailLines = stdlib.Ptr(opt.Tail)
+	}
+
+	return kubelib.FollowLogs(ctx, kubeClient, params)
+}
@@ -0,0 +1,101 @@
+package task
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Azure/k6ctl/internal/kubelib"
+)
+
+type taskLogsOption struct {
+	// Follow specifies whether to keep streaming the logs until the context is done.
+	Follow bool
+	// Since limits the logs to the ones newer than the given duration. Zero means no limit.
+	Since time.Duration
+	// Tail limits the number of lines from the end of the logs. Negative means all lines.
+	Tail int64
+	// PodNames limits the pods to read logs from. Empty means all pods of the task.
+	PodNames []string
+	// AddPrefix forces adding the pod name prefix to the logs.
+	// By default, the prefix is added only if the task runs multiple pods.
+	AddPrefix bool
+	// Output is the writer to write the logs to. Defaults to os.Stderr.
+	Output io.Writer
+	// KubeClientFactory provides the kubernetes client to use for the task.
+	// If not provided, createKubeClientFromKubeConfig is used.
+	// Unit test can provide a mock implementation.
+	KubeClientFactory kubelib.KubeClientFactory
+}
+
+func defaultTaskLogsOption() *taskLogsOption {
+	return &taskLogsOption{
+		Follow:            false,
+		Tail:              -1,
+		Output:            os.Stderr,
+		KubeClientFactory: kubelib.CreateKubeClientFromKubeConfig,
+	}
+}
+
+// TaskLogsOption configures the behavior of TaskLogs.
+type TaskLogsOption interface {
+	apply(option *taskLogsOption) error
+}
+
+type applyTaskLogsOptionFunc func(option *taskLogsOption) error
+
+func (f applyTaskLogsOptionFunc) apply(option *taskLogsOption) error {
+	return f(option)
+}
+
+// WithLogsFollow specifies whether to keep streaming the logs.
+func WithLogsFollow(follow bool) TaskLogsOption {
+	return applyTaskLogsOptionFunc(func(option *taskLogsOption) error {
+		option.Follow = follow
+		return nil
+	})
+}
+
+// WithLogsSince limits the logs to the ones newer than the given duration.
+func WithLogsSince(since time.Duration) TaskLogsOption {
+	return applyTaskLogsOptionFunc(func(option *taskLogsOption) error {
+		if since < 0 {
+			return fmt.Errorf("invalid since duration %s", since)
+		}
+		option.Since = since
+		return nil
+	})
+}
+
+// WithLogsTail limits the number of lines from the end of the logs. Negative means all lines.
+func WithLogsTail(lines int64) TaskLogsOption {
+	return applyTaskLogsOptionFunc(func(option *taskLogsOption) error {
+		option.Tail = lines
+		return nil
+	})
+}
+
+// WithLogsPods limits the pods to read logs from.
+func WithLogsPods(podNames ...string) TaskLogsOption {
+	return applyTaskLogsOptionFunc(func(option *taskLogsOption) error {
+		option.PodNames = append(option.PodNames, podNames...)
+		return nil
+	})
+}
+
+// WithLogsPrefix forces adding the pod name prefix to the logs.
+func WithLogsPrefix(addPrefix bool) TaskLogsOption {
+	return applyTaskLogsOptionFunc(func(option *taskLogsOption) error {
+		option.AddPrefix = addPrefix
+		return nil
+	})
+}
+
+// WithLogsOutput specifies the writer to write the logs to.
+func WithLogsOutput(output io.Writer) TaskLogsOption {
+	return applyTaskLogsOptionFunc(func(option *taskLogsOption) error {
+		option.Output = output
+		return nil
+	})
+}
@@ -0,0 +1,102 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+func TestTaskLogs(t *testing.T) {
+	const namespace = "test"
+
+	pod := func(name string, phase k8scorev1.PodPhase) *k8scorev1.Pod {
+		return &k8scorev1.Pod{
+			ObjectMeta: k8smetav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{labelKeyTaskName: "test"},
+			},
+			Spec: k8scorev1.PodSpec{
+				Containers: []k8scorev1.Container{{Name: containerNameRunner}},
+			},
+			Status: k8scorev1.PodStatus{Phase: phase},
+		}
+	}
+
+	kubeClient := fake.NewSimpleClientset(
+		pod("k6ctl-job-test-a", k8scorev1.PodSucceeded),
+		pod("k6ctl-job-test-b", k8scorev1.PodRunning),
+		pod("k6ctl-job-test-c", k8scorev1.PodPending),
+	)
+
+	fakeTarget := &target.StaticTarget{
+		Kubeconfig: "/tmp/fake-kubeconfig",
+	}
+	withFakeClient := applyTaskLogsOptionFunc(func(option *taskLogsOption) error {
+		option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+			return kubeClient, nil
+		}
+		return nil
+	})
+
+	ctx := context.Background()
+
+	t.Run("all pods", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		err := TaskLogs(
+			ctx, fakeTarget, TaskRef{Namespace: namespace, Name: "test"},
+			withFakeClient,
+			WithLogsPrefix(true),
+			WithLogsOutput(out),
+		)
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), "[test/k6ctl-job-test-a] fake logs")
+		assert.Contains(t, out.String(), "[test/k6ctl-job-test-b] fake logs")
+		assert.NotContains(t, out.String(), "k6ctl-job-test-c")
+	})
+
+	t.Run("filter pods", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		err := TaskLogs(
+			ctx, fakeTarget, TaskRef{Namespace: namespace, Name: "test"},
+			withFakeClient,
+			WithLogsPods("k6ctl-job-test-b"),
+			WithLogsTail(10),
+			WithLogsOutput(out),
+		)
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), "fake logs")
+		assert.NotContains(t, out.String(), "k6ctl-job-test-a")
+		assert.NotContains(t, out.String(), "[test/k6ctl-job-test-b]")
+	})
+
+	t.Run("list pods failure", func(t *testing.T) {
+		failingClient := fake.NewSimpleClientset(pod("k6ctl-job-test-a", k8scorev1.PodSucceeded))
+		failingClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, assert.AnError
+		})
+
+		err := TaskLogs(
+			ctx, fakeTarget, TaskRef{Namespace: namespace, Name: "test"},
+			applyTaskLogsOptionFunc(func(option *taskLogsOption) error {
+				option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+					return failingClient, nil
+				}
+				return nil
+			}),
+			WithLogsOutput(new(bytes.Buffer)),
+		)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "list pods")
+	})
+}
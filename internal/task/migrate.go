@@ -0,0 +1,198 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+const (
+	// SchemaVersionV1 is the first versioned task config schema.
+	// Unversioned configs predate it and share its layout.
+	SchemaVersionV1 = "v1"
+	// LatestSchemaVersion is the task config schema version implemented by Schema.
+	LatestSchemaVersion = SchemaVersionV1
+)
+
+// schemaVersionKey is the key of the version field in the task config.
+const schemaVersionKey = "version"
+
+// schemaMigration converts the task config document from a schema version to the next one.
+type schemaMigration struct {
+	From string
+	To   string
+	// Migrate rewrites the root mapping of the document in place.
+	// Comments attached to the kept nodes are preserved.
+	Migrate func(doc *ast.MappingNode) error
+}
+
+// schemaMigrations are applied in order to bring the task config to LatestSchemaVersion.
+var schemaMigrations = []schemaMigration{
+	{
+		From: "",
+		To:   SchemaVersionV1,
+		// v1 only starts versioning the schema
+		Migrate: func(*ast.MappingNode) error { return nil },
+	},
+}
+
+// supportedSchemaVersions lists the versions that can be loaded, for reporting.
+func supportedSchemaVersions() []string {
+	var rv []string
+	for _, m := range schemaMigrations {
+		if m.From != "" {
+			rv = append(rv, m.From)
+		}
+	}
+	return append(rv, LatestSchemaVersion)
+}
+
+// DisplaySchemaVersion returns the version for display, unversioned configs are reported as "unversioned".
+func DisplaySchemaVersion(version string) string {
+	if version == "" {
+		return "unversioned"
+	}
+	return version
+}
+
+// schemaDocument returns the root mapping of the task config document.
+// A root mapping with a single key is normalized to a mapping node in the file.
+func schemaDocument(file string, f *ast.File) (*ast.MappingNode, *SchemaError) {
+	if len(f.Docs) == 0 || f.Docs[0].Body == nil {
+		return nil, &SchemaError{File: file, Message: "task config is empty"}
+	}
+
+	switch body := f.Docs[0].Body.(type) {
+	case *ast.MappingNode:
+		return body, nil
+	case *ast.MappingValueNode:
+		rv := ast.Mapping(body.GetToken(), false, body)
+		f.Docs[0].Body = rv
+		return rv, nil
+	default:
+		rv := &SchemaError{File: file, Message: "task config must be a mapping"}
+		rv.Line, rv.Column = nodePosition(body)
+		return nil, rv
+	}
+}
+
+// schemaVersionField returns the version field of the task config document, or nil if it's absent.
+func schemaVersionField(doc *ast.MappingNode) *ast.MappingValueNode {
+	for _, v := range doc.Values {
+		if v.Key.String() == schemaVersionKey {
+			return v
+		}
+	}
+	return nil
+}
+
+// schemaVersionOf reads the version of the task config document. It's empty for unversioned configs.
+func schemaVersionOf(doc *ast.MappingNode) string {
+	field := schemaVersionField(doc)
+	if field == nil {
+		return ""
+	}
+
+	switch value := field.Value.(type) {
+	case *ast.NullNode:
+		return ""
+	case *ast.StringNode:
+		return value.Value
+	default:
+		return strings.TrimSpace(value.String())
+	}
+}
+
+// migrateSchemaDocument converts the task config document to LatestSchemaVersion in place,
+// and returns the version it's converted from. The version field is left unchanged.
+func migrateSchemaDocument(file string, doc *ast.MappingNode) (string, *SchemaError) {
+	from := schemaVersionOf(doc)
+
+	version := from
+	for version != LatestSchemaVersion {
+		idx := -1
+		for i, m := range schemaMigrations {
+			if m.From == version {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			rv := &SchemaError{
+				File: file,
+				Path: schemaVersionKey,
+				Message: fmt.Sprintf(
+					"unsupported version %q, supported versions are %s. The config may require a newer k6ctl",
+					version, strings.Join(supportedSchemaVersions(), ", "),
+				),
+			}
+			if field := schemaVersionField(doc); field != nil {
+				rv.Line, rv.Column = nodePosition(field.Value)
+			}
+			return "", rv
+		}
+
+		m := schemaMigrations[idx]
+		if err := m.Migrate(doc); err != nil {
+			return "", &SchemaError{
+				File:    file,
+				Message: fmt.Sprintf("migrate from %s to %s: %s", DisplaySchemaVersion(m.From), m.To, err),
+			}
+		}
+		version = m.To
+	}
+
+	return from, nil
+}
+
+// setSchemaVersion sets the version field of the task config document, adding it as the first field if absent.
+func setSchemaVersion(doc *ast.MappingNode, version string) error {
+	f, err := parser.ParseBytes([]byte(fmt.Sprintf("%s: %s\n", schemaVersionKey, version)), 0)
+	if err != nil {
+		return err
+	}
+	field, ok := f.Docs[0].Body.(*ast.MappingValueNode)
+	if !ok {
+		return fmt.Errorf("unexpected version node %T", f.Docs[0].Body)
+	}
+
+	if existing := schemaVersionField(doc); existing != nil {
+		existing.Value = field.Value
+		return nil
+	}
+	doc.Values = append([]*ast.MappingValueNode{field}, doc.Values...)
+	return nil
+}
+
+// MigrateSchema converts the task config source to LatestSchemaVersion, keeping the comments.
+// It returns the converted source and the version it's converted from. The source is returned
+// unchanged if it's already at LatestSchemaVersion.
+func MigrateSchema(file string, source []byte) ([]byte, string, error) {
+	f, err := parser.ParseBytes(source, parser.ParseComments)
+	if err != nil {
+		return nil, "", SchemaErrors{newDecodeSchemaError(file, err)}
+	}
+	doc, schemaErr := schemaDocument(file, f)
+	if schemaErr != nil {
+		return nil, "", SchemaErrors{schemaErr}
+	}
+
+	from, schemaErr := migrateSchemaDocument(file, doc)
+	if schemaErr != nil {
+		return nil, "", SchemaErrors{schemaErr}
+	}
+	if from == LatestSchemaVersion {
+		return source, from, nil
+	}
+
+	if err := setSchemaVersion(doc, LatestSchemaVersion); err != nil {
+		return nil, "", fmt.Errorf("set version: %w", err)
+	}
+	rv := f.String()
+	if !strings.HasSuffix(rv, "\n") {
+		rv += "\n"
+	}
+	return []byte(rv), from, nil
+}
@@ -0,0 +1,116 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateSchema(t *testing.T) {
+	t.Run("unversioned", func(t *testing.T) {
+		source := `# demo task
+name: demo # the task name
+files:
+- source: run.js
+  dest: run.js
+# k6 settings
+k6:
+  namespace: default
+  image: grafana/k6
+`
+		migrated, from, err := MigrateSchema("k6ctl.yaml", []byte(source))
+		assert.NoError(t, err)
+		assert.Equal(t, "", from)
+		assert.Equal(t, `# demo task
+version: v1
+name: demo # the task name
+files:
+- source: run.js
+  dest: run.js
+# k6 settings
+k6:
+  namespace: default
+  image: grafana/k6
+`, string(migrated))
+
+		schema, err := LoadSchema(strings.NewReader(string(migrated)))
+		assert.NoError(t, err)
+		assert.Equal(t, SchemaVersionV1, schema.Version)
+	})
+
+	t.Run("single field", func(t *testing.T) {
+		migrated, from, err := MigrateSchema("k6ctl.yaml", []byte("name: demo\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, "", from)
+		assert.Equal(t, "version: v1\nname: demo\n", string(migrated))
+	})
+
+	t.Run("null version", func(t *testing.T) {
+		migrated, from, err := MigrateSchema("k6ctl.yaml", []byte("version:\nname: demo\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, "", from)
+		assert.Equal(t, "version: v1\nname: demo\n", string(migrated))
+	})
+
+	t.Run("latest", func(t *testing.T) {
+		source := "version: v1\nname:   demo # kept as is\n"
+		migrated, from, err := MigrateSchema("k6ctl.yaml", []byte(source))
+		assert.NoError(t, err)
+		assert.Equal(t, LatestSchemaVersion, from)
+		assert.Equal(t, source, string(migrated))
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, _, err := MigrateSchema("k6ctl.yaml", []byte("name: demo\nversion: v9\n"))
+		assert.EqualError(
+			t, err,
+			`k6ctl.yaml:2:10: version: unsupported version "v9", supported versions are v1. The config may require a newer k6ctl`,
+		)
+	})
+
+	t.Run("not a mapping", func(t *testing.T) {
+		_, _, err := MigrateSchema("k6ctl.yaml", []byte("- name: demo\n"))
+		assert.ErrorContains(t, err, "task config must be a mapping")
+	})
+}
+
+func TestMigrateSchema_Chain(t *testing.T) {
+	original := schemaMigrations
+	defer func() { schemaMigrations = original }()
+
+	// a hypothetical v0 naming the task by title
+	schemaMigrations = append([]schemaMigration{{
+		From: "v0",
+		To:   "",
+		Migrate: func(doc *ast.MappingNode) error {
+			for _, v := range doc.Values {
+				if v.Key.String() == "title" {
+					key, ok := v.Key.(*ast.StringNode)
+					if !ok {
+						return fmt.Errorf("unexpected key %T", v.Key)
+					}
+					key.Value = "name"
+					key.GetToken().Value = "name"
+				}
+			}
+			return nil
+		},
+	}}, original...)
+
+	source := "version: v0\n# the task name\ntitle: demo\nk6:\n  namespace: default\n"
+	migrated, from, err := MigrateSchema("k6ctl.yaml", []byte(source))
+	assert.NoError(t, err)
+	assert.Equal(t, "v0", from)
+	assert.Equal(t, "version: v1\n# the task name\nname: demo\nk6:\n  namespace: default\n", string(migrated))
+
+	schema, err := LoadSchema(strings.NewReader(source))
+	assert.NoError(t, err)
+	assert.Equal(t, "demo", schema.Name)
+	assert.Equal(t, LatestSchemaVersion, schema.Version)
+
+	_, _, err = MigrateSchema("k6ctl.yaml", []byte("version: v9\n"))
+	assert.ErrorContains(t, err, "supported versions are v0, v1")
+}
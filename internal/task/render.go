@@ -0,0 +1,82 @@
+package task
+
+import (
+	"fmt"
+	"io"
+
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// redactedValue replaces the secret values in the rendered objects.
+const redactedValue = "<redacted>"
+
+// redactSecret returns a copy of the secret with the values replaced by redactedValue.
+// Redacted values are rendered in stringData to keep them readable.
+func redactSecret(secret *k8scorev1.Secret) *k8scorev1.Secret {
+	rv := secret.DeepCopy()
+	if len(rv.Data) == 0 && len(rv.StringData) == 0 {
+		return rv
+	}
+
+	stringData := map[string]string{}
+	for k := range rv.Data {
+		stringData[k] = redactedValue
+	}
+	for k := range rv.StringData {
+		stringData[k] = redactedValue
+	}
+	rv.Data = nil
+	rv.StringData = stringData
+
+	return rv
+}
+
+// renderObjects writes the objects as multi-document YAML in the order they are applied.
+func renderObjects(w io.Writer, objects *taskObjects, showSecrets bool) error {
+	var docs []any
+
+	for _, secret := range objects.Secrets {
+		if !showSecrets {
+			secret = redactSecret(secret)
+		} else {
+			secret = secret.DeepCopy()
+		}
+		secret.TypeMeta = k8smetav1.TypeMeta{APIVersion: "v1", Kind: "Secret"}
+		docs = append(docs, secret)
+	}
+	for _, configMap := range objects.ConfigMaps {
+		configMap = configMap.DeepCopy()
+		configMap.TypeMeta = k8smetav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"}
+		docs = append(docs, configMap)
+	}
+	if objects.CronJob != nil {
+		cronJob := objects.CronJob.DeepCopy()
+		cronJob.TypeMeta = k8smetav1.TypeMeta{APIVersion: k8sbatchv1.SchemeGroupVersion.String(), Kind: "CronJob"}
+		docs = append(docs, cronJob)
+	}
+	if objects.Job != nil {
+		job := objects.Job.DeepCopy()
+		job.TypeMeta = k8smetav1.TypeMeta{APIVersion: k8sbatchv1.SchemeGroupVersion.String(), Kind: "Job"}
+		docs = append(docs, job)
+	}
+
+	for idx, doc := range docs {
+		b, err := yaml.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("failed to render object: %w", err)
+		}
+		if idx > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
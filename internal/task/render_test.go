@@ -0,0 +1,169 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/Azure/k6ctl/internal/config"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+func TestRedactSecret(t *testing.T) {
+	secret := &k8scorev1.Secret{
+		Data:       map[string][]byte{"TOKEN": []byte("token")},
+		StringData: map[string]string{"PASSWORD": "password"},
+	}
+
+	redacted := redactSecret(secret)
+	assert.Nil(t, redacted.Data)
+	assert.Equal(t, map[string]string{"TOKEN": redactedValue, "PASSWORD": redactedValue}, redacted.StringData)
+	// the original secret is not modified
+	assert.Equal(t, "password", secret.StringData["PASSWORD"])
+}
+
+func TestRenderObjects(t *testing.T) {
+	objects := &taskObjects{
+		Secrets: []*k8scorev1.Secret{
+			{
+				ObjectMeta: k8smetav1.ObjectMeta{Name: "secret"},
+				StringData: map[string]string{"PASSWORD": "password"},
+			},
+		},
+		ConfigMaps: []*k8scorev1.ConfigMap{
+			{ObjectMeta: k8smetav1.ObjectMeta{Name: "configmap"}},
+		},
+		Job: &k8sbatchv1.Job{ObjectMeta: k8smetav1.ObjectMeta{Name: "job"}},
+	}
+
+	decode := func(t *testing.T, out string) []map[string]any {
+		var rv []map[string]any
+		for _, doc := range strings.Split(out, "---\n") {
+			var obj map[string]any
+			assert.NoError(t, yaml.Unmarshal([]byte(doc), &obj))
+			rv = append(rv, obj)
+		}
+		return rv
+	}
+
+	t.Run("redacted", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, renderObjects(&out, objects, false))
+		assert.NotContains(t, out.String(), "password\n")
+
+		docs := decode(t, out.String())
+		if assert.Len(t, docs, 3) {
+			assert.Equal(t, "Secret", docs[0]["kind"])
+			assert.Equal(t, map[string]any{"PASSWORD": redactedValue}, docs[0]["stringData"])
+			assert.Equal(t, "ConfigMap", docs[1]["kind"])
+			assert.Equal(t, "v1", docs[1]["apiVersion"])
+			assert.Equal(t, "Job", docs[2]["kind"])
+			assert.Equal(t, "batch/v1", docs[2]["apiVersion"])
+		}
+	})
+
+	t.Run("show secrets", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, renderObjects(&out, objects, true))
+
+		docs := decode(t, out.String())
+		if assert.Len(t, docs, 3) {
+			assert.Equal(t, map[string]any{"PASSWORD": "password"}, docs[0]["stringData"])
+		}
+	})
+}
+
+func TestRunTask_DryRun(t *testing.T) {
+	var out bytes.Buffer
+	err := RunTask(
+		context.Background(),
+		&target.StaticTarget{},
+		config.NewRegistry().GetByName,
+		&Schema{
+			Name:  "test",
+			Files: []FileMount{{Source: "test.js", Dest: "test.js"}},
+			K6:    K6{Namespace: "test", ControllerKind: controllerKindCronJob, CronJob: K6CronJob{Schedule: "@daily"}},
+		},
+		"./testdata/integration",
+		"test.js",
+		applyRunTaskOptionFunc(func(option *runTaskOption) error {
+			option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+				return nil, errors.New("dry run should not access the cluster")
+			}
+			return nil
+		}),
+		WithDryRun(&out, false),
+	)
+	assert.NoError(t, err)
+
+	docs := strings.Split(out.String(), "---\n")
+	if assert.Len(t, docs, 2) {
+		assert.Contains(t, docs[0], "kind: ConfigMap\n")
+		assert.Contains(t, docs[1], "kind: CronJob\n")
+		assert.Contains(t, docs[1], "name: k6ctl-cronjob-test\n")
+	}
+}
+
+func TestRunTask_DryRunConfigs(t *testing.T) {
+	var resolved int
+	registry := config.NewRegistry()
+	registry.Register(config.Provide(
+		"token",
+		config.LoadForStruct[struct{}],
+		func(ctx context.Context, _ target.Target, _ struct{}) (string, error) {
+			resolved++
+			return "s3cr3t", nil
+		},
+	))
+
+	render := func(t *testing.T, showSecrets bool, providerName string) (string, error) {
+		t.Helper()
+
+		var out bytes.Buffer
+		err := RunTask(
+			context.Background(),
+			&target.StaticTarget{},
+			registry.GetByName,
+			&Schema{
+				Name:    "test",
+				Files:   []FileMount{{Source: "test.js", Dest: "test.js"}},
+				K6:      K6{Namespace: "test"},
+				Configs: []ConfigProvider{{Provider: ConfigProviderProviderSpec{Name: providerName}, Env: "TOKEN"}},
+			},
+			"./testdata/integration",
+			"test.js",
+			WithDryRun(&out, showSecrets),
+		)
+		return out.String(), err
+	}
+
+	t.Run("redacted", func(t *testing.T) {
+		resolved = 0
+		out, err := render(t, false, "token")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, resolved, "providers should not be called when the secrets are redacted")
+		assert.Contains(t, out, "TOKEN: "+redactedValue)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		_, err := render(t, false, "missing")
+		assert.ErrorContains(t, err, `no config provider "missing"`)
+	})
+
+	t.Run("show secrets", func(t *testing.T) {
+		resolved = 0
+		out, err := render(t, true, "token")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resolved)
+		assert.Contains(t, out, "TOKEN: s3cr3t")
+	})
+}
@@ -3,7 +3,10 @@ package task
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/sourcegraph/conc/iter"
 	k8sbatchv1 "k8s.io/api/batch/v1"
@@ -32,30 +35,106 @@ func RunTask(
 		}
 	}
 
+	if err := validateControllerKind(taskConfig.K6); err != nil {
+		return err
+	}
+	distribution := opt.Distribution
+	if distribution == "" {
+		distribution = taskConfig.K6.Distribution
+	}
+	if err := validateDistribution(distribution); err != nil {
+		return fmt.Errorf("invalid k6.distribution: %w", err)
+	}
+	if err := validatePlacement(taskConfig.K6.Placement); err != nil {
+		return err
+	}
+	startBarrier, startBarrierTimeout, err := resolveStartBarrier(opt, taskConfig.K6)
+	if err != nil {
+		return err
+	}
+	assets, err := resolveAssets(taskConfig.K6)
+	if err != nil {
+		return err
+	}
+
 	if s, err := filepath.Abs(filepath.Clean(sourceBaseDir)); err != nil {
 		return fmt.Errorf("invalid source base dir %q: %w", sourceBaseDir, err)
 	} else {
 		sourceBaseDir = s
 	}
 
-	kubeconfig, ok := target.GetKubeconfig()
-	if !ok {
-		return fmt.Errorf("target does not have kubeconfig")
+	if err := ValidateThresholds(taskConfig.Thresholds); err != nil {
+		return err
 	}
-	kubeClient, err := opt.KubeClientFactory(kubeconfig)
+	// thresholds are evaluated against the collected summaries
+	summary := opt.Summary || taskConfig.K6.Summary.Enabled || len(taskConfig.Thresholds) > 0
+
+	vendor, err := resolveVendor(opt, taskConfig.Vendor, sourceBaseDir)
 	if err != nil {
-		return fmt.Errorf("failed to create kubernetes client: %w", err)
+		return err
+	}
+
+	var archiveSource string
+	if opt.BuildArchive {
+		archive, cleanup, err := buildScriptArchive(
+			ctx, opt.BuildK6Archive, opt.K6Binary, sourceBaseDir, taskConfig.Files, script,
+		)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		archiveSource, script = archive, k6ArchivePath(script)
+	}
+
+	var kubeClient kubernetes.Interface
+	uploadK6Assets := opt.UploadK6Assets
+	if opt.DryRunOutput == nil {
+		// dry run renders the objects without accessing the cluster
+		kubeconfig, ok := target.GetKubeconfig()
+		if !ok {
+			return fmt.Errorf("target does not have kubeconfig")
+		}
+		kubeClient, err = opt.KubeClientFactory(kubeconfig)
+		if err != nil {
+			return fmt.Errorf("failed to create kubernetes client: %w", err)
+		}
+		if uploadK6Assets == nil {
+			uploadK6Assets = uploadK6AssetsViaExec(kubeconfig)
+		}
+	}
+
+	runID := opt.RunID
+	if runID == "" {
+		runID = NewRunID()
 	}
 
 	tr := &taskRunner{
 		target:                  target,
+		runID:                   runID,
 		kubeClient:              kubeClient,
 		instances:               opt.Instances,
+		distribution:            distribution,
 		followLogs:              opt.FollowLogs,
+		waitForCompletion:       opt.WaitForCompletion,
+		waitInterval:            opt.WaitInterval,
+		startBarrier:            startBarrier,
+		startBarrierTimeout:     startBarrierTimeout,
+		resumeK6Instance:        opt.ResumeK6Instance,
+		assets:                  assets,
+		uploadK6Assets:          uploadK6Assets,
+		summary:                 summary,
+		summaryFile:             resolveSummaryFile(opt, taskConfig.K6, taskConfig.Name, runID),
+		collectK6Summary:        opt.CollectK6Summary,
+		dryRunOutput:            opt.DryRunOutput,
+		showSecrets:             opt.ShowSecrets,
+		dependenciesOutput:      opt.DependenciesOutput,
 		getConfigProviderByName: getConfigProviderByName,
 		taskConfig:              taskConfig,
 		sourceBaseDir:           sourceBaseDir,
 		script:                  script,
+		archiveSource:           archiveSource,
+		vendor:                  vendor,
+		fetchRemoteModule:       opt.FetchRemoteModule,
 	}
 
 	return tr.Run(ctx)
@@ -63,14 +142,41 @@ func RunTask(
 
 type taskRunner struct {
 	target     target.Target
+	runID      string
 	kubeClient kubernetes.Interface
 	followLogs bool
 	instances  int32
 
+	distribution string
+
+	waitForCompletion bool
+	waitInterval      time.Duration
+
+	startBarrier        bool
+	startBarrierTimeout time.Duration
+	resumeK6Instance    ResumeK6Instance
+
+	assets         assetsSettings
+	uploadK6Assets UploadK6Assets
+
+	summary          bool
+	summaryFile      string
+	collectK6Summary CollectK6Summary
+
+	dryRunOutput io.Writer
+	showSecrets  bool
+
+	dependenciesOutput io.Writer
+
 	getConfigProviderByName config.GetConfigProviderByName
 	taskConfig              *Schema
 	sourceBaseDir           string
 	script                  string
+	// archiveSource is the k6 archive built from the script, see WithArchive.
+	archiveSource string
+
+	vendor            vendorSettings
+	fetchRemoteModule FetchRemoteModule
 }
 
 type createOrUpdateClient[T any] interface {
@@ -97,91 +203,313 @@ func createOrUpdateObject[T any](
 	return client.Update(ctx, obj, k8smetav1.UpdateOptions{})
 }
 
-func (tr *taskRunner) Run(ctx context.Context) error {
-	var (
-		secretsToCreate    []*k8scorev1.Secret
-		configMapsToCreate []*k8scorev1.ConfigMap
-		jobsToCreate       []*k8sbatchv1.Job
-	)
+// taskObjects are the objects created for a run of the task.
+type taskObjects struct {
+	Secrets    []*k8scorev1.Secret
+	ConfigMaps []*k8scorev1.ConfigMap
+	// Job is the job to run the task. It's nil if the task is scheduled with a CronJob.
+	Job     *k8sbatchv1.Job
+	CronJob *k8sbatchv1.CronJob
+	// Uploads are the files to upload to the pods of the job, see scriptsLayout.
+	Uploads []scriptFile
+}
+
+func (o *taskObjects) podSpec() *k8scorev1.PodSpec {
+	if o.CronJob != nil {
+		return &o.CronJob.Spec.JobTemplate.Spec.Template.Spec
+	}
+	return &o.Job.Spec.Template.Spec
+}
+
+// buildObjects builds the objects to create for the run.
+func (tr *taskRunner) buildObjects(ctx context.Context) (*taskObjects, error) {
+	rv := &taskObjects{}
 
 	if len(tr.taskConfig.Configs) > 0 {
-		configs, err := tr.resolveConfigs(ctx, tr.taskConfig.Configs)
+		resolve := tr.resolveConfigs
+		if tr.dryRunOutput != nil && !tr.showSecrets {
+			// the values are redacted anyway, so the providers are not called:
+			// they may prompt for input, read the cluster or create tokens
+			resolve = tr.placeholderConfigs
+		}
+		configs, err := resolve(ctx, tr.taskConfig.Configs)
 		if err != nil {
-			return fmt.Errorf("failed to resolve configs: %w", err)
+			return nil, fmt.Errorf("failed to resolve configs: %w", err)
 		}
 		configsSecretObject, err := tr.buildConfigSecretObject(ctx, configs)
 		if err != nil {
-			return fmt.Errorf("failed to build config secret object: %w", err)
+			return nil, fmt.Errorf("failed to build config secret object: %w", err)
+		}
+		rv.Secrets = append(rv.Secrets, configsSecretObject)
+	}
+
+	scriptFiles, err := tr.resolveScriptFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve files: %w", err)
+	}
+	layout, err := planScriptsLayout(scriptFiles, tr.assets.MaxConfigMapFileSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan files: %w", err)
+	}
+	if len(layout.Uploads) > 0 && tr.isCronJob() {
+		// the files are uploaded by k6ctl, which doesn't run with the scheduled runs
+		return nil, fmt.Errorf(
+			"files larger than %s are not supported with controllerKind %q: %s",
+			formatByteSize(tr.assets.MaxConfigMapFileSize), controllerKindCronJob, layout.Uploads[0].Path,
+		)
+	}
+	if err := tr.describeScriptsLayout(os.Stderr, layout); err != nil {
+		return nil, err
+	}
+	for idx, shard := range layout.Shards {
+		scriptsConfigMapObject, err := tr.buildScriptsConfigMapObject(ctx, idx, shard)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build scripts config map object: %w", err)
 		}
-		secretsToCreate = append(secretsToCreate, configsSecretObject)
+		rv.ConfigMaps = append(rv.ConfigMaps, scriptsConfigMapObject)
+	}
+	rv.Uploads = layout.Uploads
+
+	if tr.useExecutionSegments() {
+		segmentsConfigMapObject, err := tr.buildSegmentsConfigMapObject()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build segments config map object: %w", err)
+		}
+		rv.ConfigMaps = append(rv.ConfigMaps, segmentsConfigMapObject)
+	}
+
+	jobObject, err := tr.buildJobObject(layout)
+	if err != nil {
+		return nil, err
+	}
+	if tr.isCronJob() {
+		rv.CronJob = tr.buildCronJobObject(jobObject)
+	} else {
+		rv.Job = jobObject
 	}
 
-	if len(tr.taskConfig.Files) > 0 {
-		scriptsConfigMapObject, err := tr.buildScriptsConfigMapObject(ctx)
+	return rv, nil
+}
+
+func (tr *taskRunner) Run(ctx context.Context) error {
+	if tr.dryRunOutput != nil {
+		objects, err := tr.buildObjects(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to build scripts config map object: %w", err)
+			return err
+		}
+		return renderObjects(tr.dryRunOutput, objects, tr.showSecrets)
+	}
+
+	if tr.isCronJob() {
+		if _, err := fmt.Fprintf(
+			os.Stderr,
+			"Scheduling task %s/%s (select objects with -l %s=%s)\n",
+			tr.objectNamespace(), tr.taskConfig.Name, labelKeyTaskName, tr.taskConfig.Name,
+		); err != nil {
+			return err
 		}
-		configMapsToCreate = append(configMapsToCreate, scriptsConfigMapObject)
+	} else if _, err := fmt.Fprintf(
+		os.Stderr,
+		"Starting task %s/%s with run ID %s (select objects with -l %s=%s)\n",
+		tr.objectNamespace(), tr.taskConfig.Name, tr.runID, labelKeyRunID, tr.runID,
+	); err != nil {
+		return err
 	}
 
-	jobObject, err := tr.buildJobObject()
+	objects, err := tr.buildObjects(ctx)
 	if err != nil {
 		return err
 	}
-	jobsToCreate = append(jobsToCreate, jobObject)
+	if err := tr.checkPlacement(ctx, os.Stderr, objects.podSpec()); err != nil {
+		return err
+	}
+	var assetsArchive []byte
+	if len(objects.Uploads) > 0 {
+		// built before creating the objects, so unreadable files don't leave the pods waiting
+		assetsArchive, err = buildAssetsArchive(objects.Uploads)
+		if err != nil {
+			return fmt.Errorf("failed to build files archive: %w", err)
+		}
+	}
 
 	secretsClient := tr.kubeClient.CoreV1().Secrets(tr.objectNamespace())
 	configMapsClient := tr.kubeClient.CoreV1().ConfigMaps(tr.objectNamespace())
 	jobsClient := tr.kubeClient.BatchV1().Jobs(tr.objectNamespace())
 
-	for _, secret := range secretsToCreate {
-		_, err := createOrUpdateObject(ctx, secretsClient, secret)
+	var (
+		secretsCreated    []*k8scorev1.Secret
+		configMapsCreated []*k8scorev1.ConfigMap
+	)
+	for _, secret := range objects.Secrets {
+		secretCreated, err := createOrUpdateObject(ctx, secretsClient, secret)
 		if err != nil {
 			return fmt.Errorf("failed to create secret %q: %w", secret.Name, err)
 		}
+		secretsCreated = append(secretsCreated, secretCreated)
 	}
-	for _, configMap := range configMapsToCreate {
-		_, err := createOrUpdateObject(ctx, configMapsClient, configMap)
+	for _, configMap := range objects.ConfigMaps {
+		configMapCreated, err := createOrUpdateObject(ctx, configMapsClient, configMap)
 		if err != nil {
 			return fmt.Errorf("failed to create config map %q: %w", configMap.Name, err)
 		}
+		configMapsCreated = append(configMapsCreated, configMapCreated)
 	}
-	for _, job := range jobsToCreate {
-		_, err := createOrUpdateObject(ctx, jobsClient, job)
-		if err != nil {
-			return fmt.Errorf("failed to create job %q: %w", job.Name, err)
+	if objects.CronJob != nil {
+		return tr.applyCronJob(ctx, objects.CronJob)
+	}
+
+	jobCreated, err := createOrUpdateObject(ctx, jobsClient, objects.Job)
+	if err != nil {
+		return fmt.Errorf("failed to create job %q: %w", objects.Job.Name, err)
+	}
+	if err := tr.setJobOwner(ctx, jobCreated, secretsCreated, configMapsCreated); err != nil {
+		return tr.abortJob(ctx, jobCreated, err)
+	}
+	if assetsArchive != nil {
+		if err := tr.uploadAssets(ctx, jobCreated, assetsArchive); err != nil {
+			return err
+		}
+	}
+
+	return tr.trackJob(ctx, jobCreated)
+}
+
+// setJobOwner makes the job the owner of the secrets and config maps of the run, so they are
+// garbage-collected with the job once its TTL expires. They are created before the job, so its pods
+// don't wait for them, and updated after the job is created.
+func (tr *taskRunner) setJobOwner(
+	ctx context.Context,
+	job *k8sbatchv1.Job,
+	secrets []*k8scorev1.Secret,
+	configMaps []*k8scorev1.ConfigMap,
+) error {
+	ownerRef := *k8smetav1.NewControllerRef(job, k8sbatchv1.SchemeGroupVersion.WithKind("Job"))
+
+	secretsClient := tr.kubeClient.CoreV1().Secrets(job.Namespace)
+	for _, secret := range secrets {
+		secret.OwnerReferences = []k8smetav1.OwnerReference{ownerRef}
+		if _, err := secretsClient.Update(ctx, secret, k8smetav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to set owner of secret %q: %w", secret.Name, err)
+		}
+	}
+	configMapsClient := tr.kubeClient.CoreV1().ConfigMaps(job.Namespace)
+	for _, configMap := range configMaps {
+		configMap.OwnerReferences = []k8smetav1.OwnerReference{ownerRef}
+		if _, err := configMapsClient.Update(ctx, configMap, k8smetav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to set owner of config map %q: %w", configMap.Name, err)
+		}
+	}
+	return nil
+}
+
+// trackJob follows the logs and waits for the created job as configured.
+func (tr *taskRunner) trackJob(ctx context.Context, job *k8sbatchv1.Job) error {
+	if tr.startBarrier {
+		if err := tr.waitForStartBarrier(ctx, job); err != nil {
+			return err
+		}
+	}
+
+	if !tr.waitForCompletion {
+		if tr.summary {
+			if _, err := fmt.Fprintln(os.Stderr, "Summary is not collected as the run is not waited for"); err != nil {
+				return err
+			}
+		}
+		if tr.followLogs {
+			return tr.followJobLogs(ctx, job, nil)
 		}
+		return nil
 	}
 
+	return tr.waitForJob(ctx, job)
+}
+
+// waitForJob waits for the job to finish while following its logs if needed,
+// then checks the result of the job.
+func (tr *taskRunner) waitForJob(ctx context.Context, job *k8sbatchv1.Job) error {
+	jobFinished := make(chan struct{})
+	logsDone := make(chan error, 1)
 	if tr.followLogs {
-		return tr.followJobLogs(ctx, jobObject)
+		go func() {
+			logsDone <- tr.followJobLogs(ctx, job, jobFinished)
+		}()
+	} else {
+		logsDone <- nil
 	}
 
-	return nil
+	finishedJob, err := tr.waitForJobCompletion(ctx, job, tr.waitInterval)
+	close(jobFinished)
+	logsErr := <-logsDone
+	if err != nil {
+		return err
+	}
+	if logsErr != nil {
+		return fmt.Errorf("failed to follow logs: %w", logsErr)
+	}
+
+	if !tr.summary {
+		return tr.checkJobResult(ctx, finishedJob)
+	}
+
+	thresholdResults, evaluated, err := tr.collectSummary(ctx, finishedJob)
+	if err != nil {
+		return fmt.Errorf("failed to collect summary: %w", err)
+	}
+	jobErr := tr.checkJobResult(ctx, finishedJob)
+	if !evaluated {
+		return jobErr
+	}
+	return resolveThresholdsResult(jobErr, thresholdResults)
 }
 
 func (tr *taskRunner) taskJobName() string {
-	return fmt.Sprintf("k6ctl-job-%s", tr.taskConfig.Name)
+	return fmt.Sprintf("k6ctl-job-%s-%s", tr.taskConfig.Name, tr.runID)
+}
+
+// sharedObjectNameSuffix returns the name suffix of the objects referenced by the job.
+// Objects referenced by a CronJob are shared by all its runs, so the run ID is not included.
+func (tr *taskRunner) sharedObjectNameSuffix() string {
+	if tr.isCronJob() {
+		return tr.taskConfig.Name
+	}
+	return fmt.Sprintf("%s-%s", tr.taskConfig.Name, tr.runID)
 }
 
 func (tr *taskRunner) configsSecretName() string {
-	return fmt.Sprintf("k6ctl-configs-secret-%s", tr.taskConfig.Name)
+	return fmt.Sprintf("k6ctl-configs-secret-%s", tr.sharedObjectNameSuffix())
 }
 
-func (tr *taskRunner) scriptsConfigMapName() string {
-	return fmt.Sprintf("k6ctl-scripts-config-%s", tr.taskConfig.Name)
+// scriptsConfigMapName returns the name of the scripts ConfigMap of the shard.
+// The first shard keeps the unsharded name.
+func (tr *taskRunner) scriptsConfigMapName(shard int) string {
+	if shard == 0 {
+		return fmt.Sprintf("k6ctl-scripts-config-%s", tr.sharedObjectNameSuffix())
+	}
+	return fmt.Sprintf("k6ctl-scripts-config-%s-%d", tr.sharedObjectNameSuffix(), shard+1)
 }
 
-func (tr *taskRunner) objectNamespace() string {
-	return tr.taskConfig.K6.Namespace
+// taskRef returns the reference to the objects of this run.
+func (tr *taskRunner) taskRef() TaskRef {
+	return TaskRef{
+		Namespace: tr.objectNamespace(),
+		Name:      tr.taskConfig.Name,
+		RunID:     tr.runID,
+	}
 }
 
-func isChildPath(basePath string, childPath string) bool { // TODO: revisit this implementation
-	relPath, err := filepath.Rel(basePath, childPath)
-	if err != nil {
-		return false
+// objectLabels returns the labels to set on the objects of this run.
+// Objects of a CronJob are shared by all its runs, so the run ID is not included.
+// The runs scheduled by the CronJob are identified by their job names instead.
+func (tr *taskRunner) objectLabels() map[string]string {
+	if tr.isCronJob() {
+		return TaskRef{Namespace: tr.objectNamespace(), Name: tr.taskConfig.Name}.labels()
 	}
-	return relPath != ".." && !filepath.IsAbs(relPath)
+	return tr.taskRef().labels()
+}
+
+func (tr *taskRunner) objectNamespace() string {
+	return tr.taskConfig.K6.Namespace
 }
 
 type resolvedConfig struct {
@@ -198,6 +526,22 @@ func (tr *taskRunner) resolveConfigs(
 	})
 }
 
+// placeholderConfigs returns the redacted placeholder values of the configs without resolving them.
+func (tr *taskRunner) placeholderConfigs(
+	_ context.Context,
+	configProviders []ConfigProvider,
+) ([]resolvedConfig, error) {
+	rv := make([]resolvedConfig, 0, len(configProviders))
+	for _, cp := range configProviders {
+		if _, ok := tr.getConfigProviderByName(cp.Provider.Name); !ok {
+			return nil, fmt.Errorf("no config provider %q", cp.Provider.Name)
+		}
+		rv = append(rv, resolvedConfig{Value: redactedValue, Env: cp.Env})
+	}
+
+	return rv, nil
+}
+
 func (tr *taskRunner) resolveConfig(
 	ctx context.Context,
 	configProvider ConfigProvider,
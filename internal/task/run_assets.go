@@ -0,0 +1,475 @@
+package task
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/conc/iter"
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/Azure/k6ctl/internal/kubelib"
+	"github.com/Azure/k6ctl/internal/stdlib"
+)
+
+// maxScriptsConfigMapSize is the max size of the files in a scripts ConfigMap.
+// The API server rejects ConfigMaps over 1 MiB, some room is left for the keys and metadata.
+const maxScriptsConfigMapSize int64 = 1000 * 1024
+
+const defaultAssetsUploadTimeout = 5 * time.Minute
+
+// assetsUploadedMarker is created in the scripts volume once the files are uploaded to the pod.
+const assetsUploadedMarker = ".k6ctl-uploaded"
+
+// envKeyAssetsUploadTimeout is the env var holding the seconds the init container waits for the upload.
+const envKeyAssetsUploadTimeout = "K6CTL_UPLOAD_TIMEOUT"
+
+// assetsLoaderScript copies the files mounted from ConfigMaps, given as args, to the scripts volume,
+// then waits for k6ctl to upload the other files. The marker is removed so k6 doesn't see it.
+var assetsLoaderScript = fmt.Sprintf(`set -e
+for f in "$@"; do
+  mkdir -p "$(dirname %[1]q/"$f")"
+  cp %[2]q/"$f" %[1]q/"$f"
+done
+echo "waiting for k6ctl to upload the files"
+elapsed=0
+until [ -f %[3]q ]; do
+  if [ "$elapsed" -ge "$%[4]s" ]; then
+    echo "files not uploaded within ${%[4]s}s" >&2
+    exit 1
+  fi
+  sleep 1
+  elapsed=$((elapsed + 1))
+done
+rm %[3]q
+`,
+	containerScriptsPath,
+	containerScriptsSourcePath,
+	path.Join(containerScriptsPath, assetsUploadedMarker),
+	envKeyAssetsUploadTimeout,
+)
+
+// assetsExtractScript extracts the uploaded archive from stdin to the scripts volume.
+var assetsExtractScript = fmt.Sprintf(
+	"tar -xzf - -C %q && touch %q",
+	containerScriptsPath, path.Join(containerScriptsPath, assetsUploadedMarker),
+)
+
+// UploadK6Assets streams the compressed archive of the uploaded files to the assets init container of the pod.
+type UploadK6Assets func(ctx context.Context, kubeClient kubernetes.Interface, pod *k8scorev1.Pod, archive []byte) error
+
+// uploadK6AssetsViaExec extracts the archive in the init container with exec through the API server.
+func uploadK6AssetsViaExec(kubeConfigPath string) UploadK6Assets {
+	return func(ctx context.Context, kubeClient kubernetes.Interface, pod *k8scorev1.Pod, archive []byte) error {
+		config, err := kubelib.RESTConfigFromKubeConfig(kubeConfigPath)
+		if err != nil {
+			return fmt.Errorf("load kubeconfig: %w", err)
+		}
+		return kubelib.ExecInPod(ctx, config, kubeClient, &kubelib.ExecParams{
+			Namespace: pod.Namespace,
+			PodName:   pod.Name,
+			Container: initContainerNameAssets,
+			Command:   []string{"sh", "-c", assetsExtractScript},
+			Stdin:     bytes.NewReader(archive),
+		})
+	}
+}
+
+// assetsSettings are the resolved k6.assets settings.
+type assetsSettings struct {
+	MaxConfigMapFileSize int64
+	Image                string
+	UploadTimeout        time.Duration
+}
+
+// parseMaxConfigMapFileSize parses the k6.assets.maxConfigMapFileSize quantity, e.g. 512Ki.
+func parseMaxConfigMapFileSize(s string) (int64, error) {
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+	size := q.Value()
+	if size <= 0 || size > maxScriptsConfigMapSize {
+		return 0, fmt.Errorf("size %q must be positive and at most %s", s, formatByteSize(maxScriptsConfigMapSize))
+	}
+	return size, nil
+}
+
+// resolveAssets resolves the k6.assets settings with their defaults.
+func resolveAssets(k6 K6) (assetsSettings, error) {
+	rv := assetsSettings{
+		MaxConfigMapFileSize: maxScriptsConfigMapSize,
+		Image:                k6.Assets.Image,
+		UploadTimeout:        defaultAssetsUploadTimeout,
+	}
+	if rv.Image == "" {
+		rv.Image = k6.PodImage
+	}
+	if k6.Assets.MaxConfigMapFileSize != "" {
+		size, err := parseMaxConfigMapFileSize(k6.Assets.MaxConfigMapFileSize)
+		if err != nil {
+			return assetsSettings{}, fmt.Errorf("invalid k6.assets.maxConfigMapFileSize: %w", err)
+		}
+		rv.MaxConfigMapFileSize = size
+	}
+	if k6.Assets.UploadTimeout != "" {
+		d, err := time.ParseDuration(k6.Assets.UploadTimeout)
+		if err != nil || d <= 0 {
+			return assetsSettings{}, fmt.Errorf("invalid k6.assets.uploadTimeout %q", k6.Assets.UploadTimeout)
+		}
+		rv.UploadTimeout = d
+	}
+	return rv, nil
+}
+
+// scriptsLayout is how the script files are delivered to the instances.
+type scriptsLayout struct {
+	// Shards are the files mounted from ConfigMaps, one ConfigMap per shard.
+	// The shards are projected into the scripts volume together.
+	Shards [][]scriptFile
+	// Uploads are the files larger than the max ConfigMap file size. They are uploaded by k6ctl
+	// to the assets init container, which also copies the files of the shards to the scripts volume.
+	Uploads []scriptFile
+}
+
+// planScriptsLayout assigns the files sorted by path to the ConfigMap shards, filling each shard up to
+// maxScriptsConfigMapSize in order. Files larger than maxConfigMapFileSize are uploaded instead.
+func planScriptsLayout(files []scriptFile, maxConfigMapFileSize int64) (scriptsLayout, error) {
+	var (
+		rv        scriptsLayout
+		shardSize int64
+	)
+	for _, f := range files {
+		var err error
+		f.Size, err = f.size()
+		if err != nil {
+			return scriptsLayout{}, err
+		}
+		if f.Size > maxConfigMapFileSize {
+			rv.Uploads = append(rv.Uploads, f)
+			continue
+		}
+
+		size := int64(len(f.Key)) + f.Size
+		if len(rv.Shards) == 0 || shardSize+size > maxScriptsConfigMapSize {
+			rv.Shards = append(rv.Shards, nil)
+			shardSize = 0
+		}
+		rv.Shards[len(rv.Shards)-1] = append(rv.Shards[len(rv.Shards)-1], f)
+		shardSize += size
+	}
+	return rv, nil
+}
+
+// filesSize returns the total size of the files.
+func filesSize(files []scriptFile) int64 {
+	var rv int64
+	for _, f := range files {
+		rv += f.Size
+	}
+	return rv
+}
+
+// formatByteSize formats the size in bytes with binary units, e.g. 1.5 MiB.
+func formatByteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 3; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// describeScriptsLayout reports how the script files are delivered to the instances.
+func (tr *taskRunner) describeScriptsLayout(w io.Writer, layout scriptsLayout) error {
+	var shardFiles []scriptFile
+	for _, shard := range layout.Shards {
+		shardFiles = append(shardFiles, shard...)
+	}
+
+	switch len(layout.Shards) {
+	case 0:
+	case 1:
+		if _, err := fmt.Fprintf(
+			w, "Mounting %d file(s) (%s) from ConfigMap %s\n",
+			len(shardFiles), formatByteSize(filesSize(shardFiles)), tr.scriptsConfigMapName(0),
+		); err != nil {
+			return err
+		}
+	default:
+		if _, err := fmt.Fprintf(
+			w, "Mounting %d file(s) (%s) from %d ConfigMaps %s to %s projected into one volume\n",
+			len(shardFiles), formatByteSize(filesSize(shardFiles)), len(layout.Shards),
+			tr.scriptsConfigMapName(0), tr.scriptsConfigMapName(len(layout.Shards)-1),
+		); err != nil {
+			return err
+		}
+	}
+
+	if len(layout.Uploads) == 0 {
+		return nil
+	}
+	paths := make([]string, 0, len(layout.Uploads))
+	for _, f := range layout.Uploads {
+		paths = append(paths, fmt.Sprintf("%s (%s)", f.Path, formatByteSize(f.Size)))
+	}
+	_, err := fmt.Fprintf(
+		w, "Uploading %d file(s) larger than %s (%s) to each instance with the %s init container: %s\n",
+		len(layout.Uploads), formatByteSize(tr.assets.MaxConfigMapFileSize), formatByteSize(filesSize(layout.Uploads)),
+		initContainerNameAssets, strings.Join(paths, ", "),
+	)
+	return err
+}
+
+// scriptsSourceVolume returns the volume source of the files mounted from ConfigMaps.
+// Multiple shards are projected into one volume. It's nil if there is no shard.
+func (tr *taskRunner) scriptsSourceVolume(layout scriptsLayout) *k8scorev1.VolumeSource {
+	switch len(layout.Shards) {
+	case 0:
+		return nil
+	case 1:
+		return &k8scorev1.VolumeSource{
+			ConfigMap: &k8scorev1.ConfigMapVolumeSource{
+				LocalObjectReference: k8scorev1.LocalObjectReference{
+					Name: tr.scriptsConfigMapName(0),
+				},
+				Items: scriptsVolumeItems(layout.Shards[0]),
+			},
+		}
+	}
+
+	projected := &k8scorev1.ProjectedVolumeSource{}
+	for idx, shard := range layout.Shards {
+		projected.Sources = append(projected.Sources, k8scorev1.VolumeProjection{
+			ConfigMap: &k8scorev1.ConfigMapProjection{
+				LocalObjectReference: k8scorev1.LocalObjectReference{
+					Name: tr.scriptsConfigMapName(idx),
+				},
+				Items: scriptsVolumeItems(shard),
+			},
+		})
+	}
+	return &k8scorev1.VolumeSource{Projected: projected}
+}
+
+// applyScriptsLayoutToPodSpec adds the scripts volume to the pod. When files are uploaded, the scripts
+// volume is an emptyDir populated by the assets init container from the ConfigMaps and the upload.
+func (tr *taskRunner) applyScriptsLayoutToPodSpec(podSpec *k8scorev1.PodSpec, layout scriptsLayout) {
+	source := tr.scriptsSourceVolume(layout)
+	if len(layout.Uploads) == 0 {
+		if source != nil {
+			podSpec.Volumes = append(podSpec.Volumes, k8scorev1.Volume{
+				Name:         scriptsVolumeName,
+				VolumeSource: *source,
+			})
+		}
+		return
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, k8scorev1.Volume{
+		Name: scriptsVolumeName,
+		VolumeSource: k8scorev1.VolumeSource{
+			EmptyDir: &k8scorev1.EmptyDirVolumeSource{},
+		},
+	})
+
+	var args []string
+	volumeMounts := []k8scorev1.VolumeMount{
+		{
+			Name:      scriptsVolumeName,
+			MountPath: containerScriptsPath,
+		},
+	}
+	if source != nil {
+		podSpec.Volumes = append(podSpec.Volumes, k8scorev1.Volume{
+			Name:         scriptsSourceVolumeName,
+			VolumeSource: *source,
+		})
+		volumeMounts = append(volumeMounts, k8scorev1.VolumeMount{
+			Name:      scriptsSourceVolumeName,
+			MountPath: containerScriptsSourcePath,
+			ReadOnly:  true,
+		})
+		for _, shard := range layout.Shards {
+			for _, f := range shard {
+				args = append(args, f.Path)
+			}
+		}
+	}
+
+	podSpec.InitContainers = append(podSpec.InitContainers, k8scorev1.Container{
+		Name:    initContainerNameAssets,
+		Image:   tr.assets.Image,
+		Command: []string{"sh", "-c", assetsLoaderScript, initContainerNameAssets},
+		Args:    args,
+		Env: []k8scorev1.EnvVar{
+			{
+				Name:  envKeyAssetsUploadTimeout,
+				Value: strconv.Itoa(int(tr.assets.UploadTimeout.Seconds())),
+			},
+		},
+		VolumeMounts: volumeMounts,
+	})
+}
+
+// buildAssetsArchive builds the gzip compressed tar archive of the uploaded files by their paths.
+func buildAssetsArchive(files []scriptFile) ([]byte, error) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	dirs := map[string]bool{}
+	for _, f := range files {
+		var parents []string
+		for dir := path.Dir(f.Path); dir != "." && !dirs[dir]; dir = path.Dir(dir) {
+			dirs[dir] = true
+			parents = append(parents, dir)
+		}
+		for idx := len(parents) - 1; idx >= 0; idx-- {
+			if err := tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeDir,
+				Name:     parents[idx] + "/",
+				Mode:     0o755,
+			}); err != nil {
+				return nil, err
+			}
+		}
+
+		b, err := f.read()
+		if err != nil {
+			return nil, err
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     f.Path,
+			Mode:     0o644,
+			Size:     int64(len(b)),
+		}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(b); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// isAssetsInitContainerRunning reports whether the assets init container of the pod is waiting for the upload.
+func isAssetsInitContainerRunning(pod *k8scorev1.Pod) bool {
+	for _, s := range pod.Status.InitContainerStatuses {
+		if s.Name == initContainerNameAssets {
+			return s.State.Running != nil
+		}
+	}
+	return false
+}
+
+// uploadAssets uploads the archive to the pods of the job as their assets init containers start.
+// If not all instances received the files within the upload timeout, the job is deleted and the run is aborted.
+//
+// It returns once every instance received the files, so the pods created later to retry a failed instance
+// don't receive them and fail when their init container times out. The job doesn't retry by default
+// (backoffLimit 0), a warning is printed if k6.jobSpec overrides it.
+func (tr *taskRunner) uploadAssets(ctx context.Context, job *k8sbatchv1.Job, archive []byte) error {
+	instances := stdlib.ValOrZero(job.Spec.Parallelism)
+	if stdlib.ValOrZero(job.Spec.BackoffLimit) > 0 {
+		if _, err := fmt.Fprintf(
+			os.Stderr,
+			"Warning: k6.jobSpec.backoffLimit is %d, the retried instances won't receive the uploaded files\n",
+			*job.Spec.BackoffLimit,
+		); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(
+		os.Stderr,
+		"Uploading files (%s compressed) to %d instance(s) (timeout %s)...\n",
+		formatByteSize(int64(len(archive))), instances, tr.assets.UploadTimeout,
+	); err != nil {
+		return err
+	}
+
+	uploaded := map[string]bool{}
+	var pendingPods []k8scorev1.Pod
+	waitCtx, cancel := context.WithTimeout(ctx, tr.assets.UploadTimeout)
+	defer cancel()
+	err := wait.PollUntilContextCancel(waitCtx, tr.waitInterval, true, func(ctx context.Context) (bool, error) {
+		pods, err := tr.listJobPods(ctx, job)
+		if err != nil {
+			return false, err
+		}
+
+		var receiving []k8scorev1.Pod
+		pendingPods = nil
+		for _, pod := range pods {
+			switch {
+			case uploaded[pod.Name]:
+			case isAssetsInitContainerRunning(&pod):
+				receiving = append(receiving, pod)
+			default:
+				pendingPods = append(pendingPods, pod)
+			}
+		}
+
+		_, err = iter.MapErr(receiving, func(pod *k8scorev1.Pod) (struct{}, error) {
+			return struct{}{}, tr.uploadK6Assets(ctx, tr.kubeClient, pod, archive)
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, pod := range receiving {
+			uploaded[pod.Name] = true
+		}
+		return int32(len(uploaded)) >= instances, nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to upload files: %w", err)
+		}
+
+		uploadErr := fmt.Errorf("failed to upload files: %w", err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			msg := fmt.Sprintf(
+				"files not uploaded within %s: %d/%d instances received them",
+				tr.assets.UploadTimeout, len(uploaded), instances,
+			)
+			if len(pendingPods) > 0 {
+				var pending []string
+				for _, pod := range pendingPods {
+					pending = append(pending, describePendingPod(&pod))
+				}
+				msg += fmt.Sprintf(" (waiting: %s)", strings.Join(pending, ", "))
+			}
+			uploadErr = errors.New(msg)
+		}
+		// the instances would wait for the files until the init container times out, abort the run
+		return tr.abortJob(ctx, job, uploadErr)
+	}
+
+	_, err = fmt.Fprintf(os.Stderr, "Uploaded files to %d instance(s)\n", len(uploaded))
+	return err
+}
@@ -0,0 +1,348 @@
+package task
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/k6ctl/internal/config"
+	"github.com/Azure/k6ctl/internal/stdlib"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+func TestResolveAssets(t *testing.T) {
+	cases := []struct {
+		name      string
+		k6        K6
+		expected  assetsSettings
+		expectErr bool
+	}{
+		{
+			name: "defaults",
+			k6:   K6{PodImage: "grafana/k6"},
+			expected: assetsSettings{
+				MaxConfigMapFileSize: maxScriptsConfigMapSize,
+				Image:                "grafana/k6",
+				UploadTimeout:        defaultAssetsUploadTimeout,
+			},
+		},
+		{
+			name: "configured",
+			k6: K6{
+				PodImage: "grafana/k6",
+				Assets:   K6Assets{MaxConfigMapFileSize: "512Ki", Image: "busybox", UploadTimeout: "90s"},
+			},
+			expected: assetsSettings{
+				MaxConfigMapFileSize: 512 * 1024,
+				Image:                "busybox",
+				UploadTimeout:        90 * time.Second,
+			},
+		},
+		{
+			name:      "invalid size",
+			k6:        K6{Assets: K6Assets{MaxConfigMapFileSize: "large"}},
+			expectErr: true,
+		},
+		{
+			name:      "size over the ConfigMap limit",
+			k6:        K6{Assets: K6Assets{MaxConfigMapFileSize: "2Mi"}},
+			expectErr: true,
+		},
+		{
+			name:      "invalid timeout",
+			k6:        K6{Assets: K6Assets{UploadTimeout: "soon"}},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			settings, err := resolveAssets(tc.k6)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, settings)
+		})
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	assert.Equal(t, "512 B", formatByteSize(512))
+	assert.Equal(t, "1.0 KiB", formatByteSize(1024))
+	assert.Equal(t, "1000.0 KiB", formatByteSize(maxScriptsConfigMapSize))
+	assert.Equal(t, "1.5 MiB", formatByteSize(3*512*1024))
+	assert.Equal(t, "2.0 GiB", formatByteSize(2*1024*1024*1024))
+}
+
+// writeSizedFiles writes files of the given sizes under dir and returns them as script files sorted by path.
+func writeSizedFiles(t *testing.T, dir string, sizes map[string]int) []scriptFile {
+	t.Helper()
+
+	files := make([]scriptFile, 0, len(sizes))
+	for name, size := range sizes {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		assert.NoError(t, os.WriteFile(p, bytes.Repeat([]byte("x"), size), 0o644))
+		files = append(files, scriptFile{Source: p, Path: name})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	assignScriptFileKeys(files)
+	return files
+}
+
+func TestPlanScriptsLayout(t *testing.T) {
+	const kib = 1024
+	files := writeSizedFiles(t, t.TempDir(), map[string]int{
+		"a.csv":        400 * kib,
+		"b.csv":        400 * kib,
+		"c.csv":        400 * kib,
+		"data/big.csv": 1200 * kib,
+		"run.js":       kib,
+	})
+
+	t.Run("default max file size", func(t *testing.T) {
+		layout, err := planScriptsLayout(files, maxScriptsConfigMapSize)
+		assert.NoError(t, err)
+		assert.Len(t, layout.Shards, 2)
+		assert.Equal(t, []string{"a.csv", "b.csv"}, scriptFilePaths(layout.Shards[0]))
+		assert.Equal(t, []string{"c.csv", "run.js"}, scriptFilePaths(layout.Shards[1]))
+		assert.Equal(t, []string{"data/big.csv"}, scriptFilePaths(layout.Uploads))
+		assert.Equal(t, int64(1200*kib), filesSize(layout.Uploads))
+	})
+
+	t.Run("lower max file size", func(t *testing.T) {
+		layout, err := planScriptsLayout(files, 100*kib)
+		assert.NoError(t, err)
+		assert.Len(t, layout.Shards, 1)
+		assert.Equal(t, []string{"run.js"}, scriptFilePaths(layout.Shards[0]))
+		assert.Equal(t, []string{"a.csv", "b.csv", "c.csv", "data/big.csv"}, scriptFilePaths(layout.Uploads))
+	})
+
+	t.Run("no files", func(t *testing.T) {
+		layout, err := planScriptsLayout(nil, maxScriptsConfigMapSize)
+		assert.NoError(t, err)
+		assert.Empty(t, layout.Shards)
+		assert.Empty(t, layout.Uploads)
+	})
+}
+
+func TestBuildAssetsArchive(t *testing.T) {
+	files := writeSizedFiles(t, t.TempDir(), map[string]int{
+		"data/big.csv":        3,
+		"data/nested/big.bin": 5,
+		"root.json":           2,
+	})
+
+	archive, err := buildAssetsArchive(files)
+	assert.NoError(t, err)
+
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	assert.NoError(t, err)
+	tr := tar.NewReader(gr)
+	var entries []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		b, err := io.ReadAll(tr)
+		assert.NoError(t, err)
+		entries = append(entries, fmt.Sprintf("%s %d", header.Name, len(b)))
+	}
+	assert.Equal(t, []string{
+		"data/ 0",
+		"data/big.csv 3",
+		"data/nested/ 0",
+		"data/nested/big.bin 5",
+		"root.json 2",
+	}, entries)
+}
+
+func TestRunTask_LargeFiles(t *testing.T) {
+	const (
+		namespace = "test"
+		instances = 2
+		kib       = 1024
+	)
+
+	dir := t.TempDir()
+	writeSizedFiles(t, dir, map[string]int{
+		"data/a.csv":   600 * kib,
+		"data/b.csv":   600 * kib,
+		"data/big.csv": 2000 * kib,
+	})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "test.js"), []byte("export default function () {}"), 0o644))
+
+	taskConfig := &Schema{
+		Name:  "test",
+		Files: []FileMount{{Source: "test.js"}, {Source: "data"}},
+		K6:    K6{Namespace: namespace, PodImage: "grafana/k6"},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	kubeClient := fake.NewSimpleClientset()
+
+	// simulate the job controller: create the pods waiting for the upload once the job is created
+	go func() {
+		_ = wait.PollUntilContextCancel(ctx, 10*time.Millisecond, true, func(ctx context.Context) (bool, error) {
+			jobs, err := kubeClient.BatchV1().Jobs(namespace).List(ctx, k8smetav1.ListOptions{})
+			if err != nil || len(jobs.Items) == 0 {
+				return false, nil
+			}
+			job := jobs.Items[0]
+
+			for idx := 0; idx < instances; idx++ {
+				_, err := kubeClient.CoreV1().Pods(namespace).Create(ctx, &k8scorev1.Pod{
+					ObjectMeta: k8smetav1.ObjectMeta{
+						Name:      fmt.Sprintf("pod-%d", idx),
+						Namespace: namespace,
+						Labels:    job.Spec.Selector.MatchLabels,
+						OwnerReferences: []k8smetav1.OwnerReference{
+							{Kind: "Job", Name: job.Name, UID: job.UID, Controller: stdlib.Ptr(true)},
+						},
+					},
+					Status: k8scorev1.PodStatus{
+						Phase: k8scorev1.PodPending,
+						InitContainerStatuses: []k8scorev1.ContainerStatus{
+							{
+								Name:  initContainerNameAssets,
+								State: k8scorev1.ContainerState{Running: &k8scorev1.ContainerStateRunning{}},
+							},
+						},
+					},
+				}, k8smetav1.CreateOptions{})
+				if err != nil {
+					return false, err
+				}
+			}
+			return true, nil
+		})
+	}()
+
+	var (
+		mu       sync.Mutex
+		uploaded []string
+		archives [][]byte
+	)
+	err := RunTask(
+		ctx,
+		&target.StaticTarget{Kubeconfig: "/tmp/fake-kubeconfig"},
+		config.NewRegistry().GetByName,
+		taskConfig,
+		dir,
+		"test.js",
+		applyRunTaskOptionFunc(func(option *runTaskOption) error {
+			option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+				return kubeClient, nil
+			}
+			option.UploadK6Assets = func(ctx context.Context, kubeClient kubernetes.Interface, pod *k8scorev1.Pod, archive []byte) error {
+				mu.Lock()
+				defer mu.Unlock()
+				uploaded = append(uploaded, pod.Name)
+				archives = append(archives, archive)
+				return nil
+			}
+			option.WaitInterval = 10 * time.Millisecond
+
+			return nil
+		}),
+		WithInstances(instances),
+		WithRunID("large"),
+		WithFollowLogs(false),
+		WithWaitForCompletion(false),
+	)
+	assert.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.ElementsMatch(t, []string{"pod-0", "pod-1"}, uploaded)
+	assert.NotEmpty(t, archives[0])
+
+	// the small files are sharded as they don't fit in one ConfigMap
+	configMaps, err := kubeClient.CoreV1().ConfigMaps(namespace).List(ctx, k8smetav1.ListOptions{})
+	assert.NoError(t, err)
+	var configMapNames []string
+	for _, cm := range configMaps.Items {
+		configMapNames = append(configMapNames, cm.Name)
+		assert.NotContains(t, cm.Data, "data__big.csv")
+	}
+	assert.ElementsMatch(t, []string{
+		"k6ctl-scripts-config-test-large",
+		"k6ctl-scripts-config-test-large-2",
+	}, configMapNames)
+
+	job, err := kubeClient.BatchV1().Jobs(namespace).Get(ctx, "k6ctl-job-test-large", k8smetav1.GetOptions{})
+	assert.NoError(t, err)
+	podSpec := job.Spec.Template.Spec
+
+	volumes := map[string]k8scorev1.VolumeSource{}
+	for _, v := range podSpec.Volumes {
+		volumes[v.Name] = v.VolumeSource
+	}
+	assert.NotNil(t, volumes[scriptsVolumeName].EmptyDir)
+	if assert.NotNil(t, volumes[scriptsSourceVolumeName].Projected) {
+		sources := volumes[scriptsSourceVolumeName].Projected.Sources
+		assert.Len(t, sources, 2)
+		assert.Equal(t, "k6ctl-scripts-config-test-large", sources[0].ConfigMap.Name)
+		assert.Equal(t, "k6ctl-scripts-config-test-large-2", sources[1].ConfigMap.Name)
+	}
+
+	if assert.Len(t, podSpec.InitContainers, 1) {
+		initContainer := podSpec.InitContainers[0]
+		assert.Equal(t, initContainerNameAssets, initContainer.Name)
+		assert.Equal(t, "grafana/k6", initContainer.Image)
+		assert.Equal(t, []string{"data/a.csv", "data/b.csv", "test.js"}, initContainer.Args)
+		assert.Equal(t, []k8scorev1.EnvVar{{Name: envKeyAssetsUploadTimeout, Value: "300"}}, initContainer.Env)
+	}
+}
+
+func TestRunTask_LargeFilesCronJob(t *testing.T) {
+	dir := t.TempDir()
+	writeSizedFiles(t, dir, map[string]int{"big.csv": 2 * 1024})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "test.js"), []byte("export default function () {}"), 0o644))
+
+	taskConfig := &Schema{
+		Name:  "test",
+		Files: []FileMount{{Source: "test.js"}, {Source: "big.csv"}},
+		K6: K6{
+			Namespace:      "test",
+			ControllerKind: controllerKindCronJob,
+			CronJob:        K6CronJob{Schedule: "@daily"},
+			Assets:         K6Assets{MaxConfigMapFileSize: "1Ki"},
+		},
+	}
+
+	err := RunTask(
+		context.Background(),
+		&target.StaticTarget{},
+		config.NewRegistry().GetByName,
+		taskConfig,
+		dir,
+		"test.js",
+		WithDryRun(io.Discard, false),
+	)
+	assert.ErrorContains(t, err, `files larger than 1.0 KiB are not supported with controllerKind "CronJob": big.csv`)
+}
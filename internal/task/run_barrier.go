@@ -0,0 +1,207 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/conc/iter"
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/Azure/k6ctl/internal/kubelib"
+	"github.com/Azure/k6ctl/internal/stdlib"
+)
+
+const defaultStartBarrierTimeout = 5 * time.Minute
+
+// ResumeK6Instance resumes the paused k6 test in the pod.
+type ResumeK6Instance func(ctx context.Context, kubeClient kubernetes.Interface, pod *k8scorev1.Pod) error
+
+func resumeK6InstanceViaAPI(ctx context.Context, kubeClient kubernetes.Interface, pod *k8scorev1.Pod) error {
+	return kubelib.ResumeK6Pod(ctx, kubeClient, pod.Namespace, pod.Name)
+}
+
+// StartBarrierTimeoutError is returned when not all instances are ready before the barrier timeout.
+type StartBarrierTimeoutError struct {
+	Timeout   time.Duration
+	Instances int32
+	Ready     int32
+	// Pending lists the pods which are not ready yet, with their status.
+	Pending []string
+}
+
+func (e *StartBarrierTimeoutError) Error() string {
+	msg := fmt.Sprintf(
+		"start barrier not reached within %s: %d/%d instances ready",
+		e.Timeout, e.Ready, e.Instances,
+	)
+	if len(e.Pending) > 0 {
+		msg += fmt.Sprintf(" (not ready: %s)", strings.Join(e.Pending, ", "))
+	}
+	return msg
+}
+
+// resolveStartBarrier resolves the start barrier settings from the run options and the task config.
+func resolveStartBarrier(opt *runTaskOption, k6 K6) (bool, time.Duration, error) {
+	enabled := opt.StartBarrier || k6.StartBarrier.Enabled
+	if !enabled {
+		return false, 0, nil
+	}
+
+	if k6.ControllerKind == controllerKindCronJob {
+		return false, 0, fmt.Errorf("start barrier is not supported with controllerKind %q", controllerKindCronJob)
+	}
+
+	timeout := opt.StartBarrierTimeout
+	if timeout <= 0 && k6.StartBarrier.Timeout != "" {
+		d, err := time.ParseDuration(k6.StartBarrier.Timeout)
+		if err != nil {
+			return false, 0, fmt.Errorf("invalid k6.startBarrier.timeout %q: %w", k6.StartBarrier.Timeout, err)
+		}
+		timeout = d
+	}
+	if timeout <= 0 {
+		timeout = defaultStartBarrierTimeout
+	}
+
+	return true, timeout, nil
+}
+
+// applyStartBarrierToContainer makes the k6 runner start in paused state with the REST API exposed.
+// The readiness probe reports the pod as ready once the k6 REST API is serving.
+func applyStartBarrierToContainer(container *k8scorev1.Container) {
+	container.Args = append(
+		container.Args,
+		"--paused",
+		"--address", fmt.Sprintf("0.0.0.0:%d", kubelib.K6APIPort),
+	)
+	container.Ports = append(container.Ports, k8scorev1.ContainerPort{
+		Name:          "k6-api",
+		ContainerPort: kubelib.K6APIPort,
+		Protocol:      k8scorev1.ProtocolTCP,
+	})
+	container.ReadinessProbe = &k8scorev1.Probe{
+		ProbeHandler: k8scorev1.ProbeHandler{
+			HTTPGet: &k8scorev1.HTTPGetAction{
+				Path: "/v1/status",
+				Port: intstr.FromInt32(kubelib.K6APIPort),
+			},
+		},
+		PeriodSeconds: 1,
+	}
+}
+
+func isPodReady(pod *k8scorev1.Pod) bool {
+	if pod.Status.Phase != k8scorev1.PodRunning {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == k8scorev1.PodReady {
+			return c.Status == k8scorev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// describePendingPod describes why the pod is not ready yet.
+func describePendingPod(pod *k8scorev1.Pod) string {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == k8scorev1.PodScheduled && c.Status == k8scorev1.ConditionFalse {
+			return fmt.Sprintf("%s %s: %s", pod.Name, c.Reason, c.Message)
+		}
+	}
+	podStatus := buildPodStatus(*pod)
+	if podStatus.Reason != "" {
+		return fmt.Sprintf("%s %s: %s", pod.Name, pod.Status.Phase, podStatus.Reason)
+	}
+	return fmt.Sprintf("%s %s", pod.Name, pod.Status.Phase)
+}
+
+// waitForStartBarrier waits until all instances are ready in paused state, then resumes them together.
+// If the barrier is not reached within the timeout, or the instances can't be resumed, the job is deleted
+// and the run is aborted.
+func (tr *taskRunner) waitForStartBarrier(ctx context.Context, job *k8sbatchv1.Job) error {
+	instances := stdlib.ValOrZero(job.Spec.Parallelism)
+	if _, err := fmt.Fprintf(
+		os.Stderr,
+		"Waiting for %d instance(s) to be ready (timeout %s)...\n",
+		instances, tr.startBarrierTimeout,
+	); err != nil {
+		return err
+	}
+
+	var readyPods, pendingPods []k8scorev1.Pod
+	waitCtx, cancel := context.WithTimeout(ctx, tr.startBarrierTimeout)
+	defer cancel()
+	err := wait.PollUntilContextCancel(waitCtx, tr.waitInterval, true, func(ctx context.Context) (bool, error) {
+		pods, err := tr.listJobPods(ctx, job)
+		if err != nil {
+			return false, err
+		}
+
+		readyPods, pendingPods = nil, nil
+		for _, pod := range pods {
+			if isPodReady(&pod) {
+				readyPods = append(readyPods, pod)
+			} else {
+				pendingPods = append(pendingPods, pod)
+			}
+		}
+		return int32(len(readyPods)) >= instances, nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to wait for start barrier: %w", err)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			// paused instances would wait forever, abort the run
+			return tr.abortJob(ctx, job, fmt.Errorf("failed to wait for start barrier: %w", err))
+		}
+
+		barrierErr := &StartBarrierTimeoutError{
+			Timeout:   tr.startBarrierTimeout,
+			Instances: instances,
+			Ready:     int32(len(readyPods)),
+		}
+		for _, pod := range pendingPods {
+			barrierErr.Pending = append(barrierErr.Pending, describePendingPod(&pod))
+		}
+
+		// paused instances would wait forever, abort the run
+		return tr.abortJob(ctx, job, barrierErr)
+	}
+
+	// resume the instances concurrently so they start as close together as possible
+	_, err = iter.MapErr(readyPods, func(pod *k8scorev1.Pod) (struct{}, error) {
+		if err := tr.resumeK6Instance(ctx, tr.kubeClient, pod); err != nil {
+			return struct{}{}, fmt.Errorf("resume %s: %w", pod.Name, err)
+		}
+		return struct{}{}, nil
+	})
+	if err != nil {
+		// the instances not resumed would stay paused, abort the run
+		return tr.abortJob(ctx, job, fmt.Errorf("failed to start instances: %w", err))
+	}
+
+	_, err = fmt.Fprintf(os.Stderr, "Started %d instance(s)\n", len(readyPods))
+	return err
+}
+
+// abortJob deletes the job of the run failed by cause, and returns cause.
+func (tr *taskRunner) abortJob(ctx context.Context, job *k8sbatchv1.Job, cause error) error {
+	err := tr.kubeClient.BatchV1().Jobs(job.Namespace).Delete(ctx, job.Name, k8smetav1.DeleteOptions{
+		PropagationPolicy: stdlib.Ptr(k8smetav1.DeletePropagationBackground),
+	})
+	if err != nil {
+		return errors.Join(cause, fmt.Errorf("failed to abort job %q: %w", job.Name, err))
+	}
+	return cause
+}
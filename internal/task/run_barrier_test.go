@@ -0,0 +1,251 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/k6ctl/internal/config"
+	"github.com/Azure/k6ctl/internal/stdlib"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+func TestResolveStartBarrier(t *testing.T) {
+	cases := []struct {
+		name            string
+		opt             runTaskOption
+		k6              K6
+		expectedEnabled bool
+		expectedTimeout time.Duration
+		expectErr       bool
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:            "enabled by option",
+			opt:             runTaskOption{StartBarrier: true},
+			expectedEnabled: true,
+			expectedTimeout: defaultStartBarrierTimeout,
+		},
+		{
+			name:            "enabled by config",
+			k6:              K6{StartBarrier: K6StartBarrier{Enabled: true, Timeout: "90s"}},
+			expectedEnabled: true,
+			expectedTimeout: 90 * time.Second,
+		},
+		{
+			name:            "option timeout overrides config",
+			opt:             runTaskOption{StartBarrierTimeout: time.Minute},
+			k6:              K6{StartBarrier: K6StartBarrier{Enabled: true, Timeout: "90s"}},
+			expectedEnabled: true,
+			expectedTimeout: time.Minute,
+		},
+		{
+			name:      "invalid timeout",
+			k6:        K6{StartBarrier: K6StartBarrier{Enabled: true, Timeout: "soon"}},
+			expectErr: true,
+		},
+		{
+			name: "cronjob",
+			opt:  runTaskOption{StartBarrier: true},
+			k6: K6{
+				ControllerKind: controllerKindCronJob,
+				CronJob:        K6CronJob{Schedule: "@daily"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			enabled, timeout, err := resolveStartBarrier(&tc.opt, tc.k6)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedEnabled, enabled)
+			assert.Equal(t, tc.expectedTimeout, timeout)
+		})
+	}
+}
+
+func TestRunTask_StartBarrier(t *testing.T) {
+	const (
+		namespace = "test"
+		instances = 3
+	)
+
+	readyPodStatus := k8scorev1.PodStatus{
+		Phase: k8scorev1.PodRunning,
+		Conditions: []k8scorev1.PodCondition{
+			{Type: k8scorev1.PodScheduled, Status: k8scorev1.ConditionTrue},
+			{Type: k8scorev1.PodReady, Status: k8scorev1.ConditionTrue},
+		},
+	}
+	unschedulablePodStatus := k8scorev1.PodStatus{
+		Phase: k8scorev1.PodPending,
+		Conditions: []k8scorev1.PodCondition{
+			{
+				Type:    k8scorev1.PodScheduled,
+				Status:  k8scorev1.ConditionFalse,
+				Reason:  k8scorev1.PodReasonUnschedulable,
+				Message: "0/2 nodes are available",
+			},
+		},
+	}
+
+	run := func(
+		podStatuses []k8scorev1.PodStatus,
+		timeout time.Duration,
+		resumeErrs map[string]error,
+	) (kubernetes.Interface, []string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		kubeClient := fake.NewSimpleClientset()
+
+		// simulate the job controller: create the pods once the job is created
+		go func() {
+			_ = wait.PollUntilContextCancel(ctx, 10*time.Millisecond, true, func(ctx context.Context) (bool, error) {
+				jobs, err := kubeClient.BatchV1().Jobs(namespace).List(ctx, k8smetav1.ListOptions{})
+				if err != nil || len(jobs.Items) == 0 {
+					return false, nil
+				}
+				job := jobs.Items[0]
+
+				for idx, status := range podStatuses {
+					_, err := kubeClient.CoreV1().Pods(namespace).Create(ctx, &k8scorev1.Pod{
+						ObjectMeta: k8smetav1.ObjectMeta{
+							Name:      fmt.Sprintf("pod-%d", idx),
+							Namespace: namespace,
+							Labels:    job.Spec.Selector.MatchLabels,
+							OwnerReferences: []k8smetav1.OwnerReference{
+								{Kind: "Job", Name: job.Name, UID: job.UID, Controller: stdlib.Ptr(true)},
+							},
+						},
+						Status: status,
+					}, k8smetav1.CreateOptions{})
+					if err != nil {
+						return false, err
+					}
+				}
+				return true, nil
+			})
+		}()
+
+		var (
+			mu      sync.Mutex
+			resumed []string
+		)
+		err := RunTask(
+			ctx,
+			&target.StaticTarget{Kubeconfig: "/tmp/fake-kubeconfig"},
+			config.NewRegistry().GetByName,
+			&Schema{Name: "test", K6: K6{Namespace: namespace}},
+			"./testdata/integration",
+			"test.js",
+			applyRunTaskOptionFunc(func(option *runTaskOption) error {
+				option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+					return kubeClient, nil
+				}
+				option.ResumeK6Instance = func(ctx context.Context, kubeClient kubernetes.Interface, pod *k8scorev1.Pod) error {
+					if resumeErr := resumeErrs[pod.Name]; resumeErr != nil {
+						return resumeErr
+					}
+					mu.Lock()
+					defer mu.Unlock()
+					resumed = append(resumed, pod.Name)
+					return nil
+				}
+				option.WaitInterval = 10 * time.Millisecond
+
+				return nil
+			}),
+			WithInstances(instances),
+			WithRunID("barrier"),
+			WithStartBarrier(true, timeout),
+			WithFollowLogs(false),
+			WithWaitForCompletion(false),
+		)
+
+		mu.Lock()
+		defer mu.Unlock()
+		return kubeClient, resumed, err
+	}
+
+	t.Run("all instances ready", func(t *testing.T) {
+		kubeClient, resumed, err := run(
+			[]k8scorev1.PodStatus{readyPodStatus, readyPodStatus, readyPodStatus},
+			5*time.Second,
+			nil,
+		)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"pod-0", "pod-1", "pod-2"}, resumed)
+
+		job, err := kubeClient.BatchV1().Jobs(namespace).Get(context.Background(), "k6ctl-job-test-barrier", k8smetav1.GetOptions{})
+		assert.NoError(t, err)
+		container := job.Spec.Template.Spec.Containers[0]
+		assert.Subset(t, container.Args, []string{"--paused", "--address", "0.0.0.0:6565"})
+		assert.NotNil(t, container.ReadinessProbe)
+		assert.Equal(t, "/v1/status", container.ReadinessProbe.HTTPGet.Path)
+	})
+
+	t.Run("resume failures", func(t *testing.T) {
+		kubeClient, resumed, err := run(
+			[]k8scorev1.PodStatus{readyPodStatus, readyPodStatus, readyPodStatus},
+			5*time.Second,
+			map[string]error{"pod-0": assert.AnError, "pod-2": assert.AnError},
+		)
+		assert.ErrorContains(t, err, "failed to start instances")
+		assert.ErrorContains(t, err, "resume pod-0: "+assert.AnError.Error())
+		assert.ErrorContains(t, err, "resume pod-2: "+assert.AnError.Error())
+		// the other instances are still resumed
+		assert.Equal(t, []string{"pod-1"}, resumed)
+
+		// the paused instances would never finish, so the job should be removed
+		_, err = kubeClient.BatchV1().Jobs(namespace).Get(context.Background(), "k6ctl-job-test-barrier", k8smetav1.GetOptions{})
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+
+	t.Run("barrier timeout", func(t *testing.T) {
+		kubeClient, resumed, err := run(
+			[]k8scorev1.PodStatus{readyPodStatus, readyPodStatus, unschedulablePodStatus},
+			200*time.Millisecond,
+			nil,
+		)
+		assert.Empty(t, resumed)
+
+		var barrierErr *StartBarrierTimeoutError
+		if assert.ErrorAs(t, err, &barrierErr) {
+			assert.Equal(t, int32(instances), barrierErr.Instances)
+			assert.Equal(t, int32(2), barrierErr.Ready)
+			assert.Equal(t, []string{"pod-2 Unschedulable: 0/2 nodes are available"}, barrierErr.Pending)
+		}
+
+		// the aborted job should be removed
+		_, err = kubeClient.BatchV1().Jobs(namespace).Get(context.Background(), "k6ctl-job-test-barrier", k8smetav1.GetOptions{})
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+}
+
+func TestStartBarrierTimeoutError(t *testing.T) {
+	err := &StartBarrierTimeoutError{
+		Timeout:   time.Minute,
+		Instances: 2,
+		Ready:     1,
+		Pending:   []string{"pod-1 Pending"},
+	}
+	assert.Equal(t, "start barrier not reached within 1m0s: 1/2 instances ready (not ready: pod-1 Pending)", err.Error())
+}
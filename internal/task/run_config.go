@@ -4,7 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
+	"sort"
+	"unicode/utf8"
 
 	k8scorev1 "k8s.io/api/core/v1"
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,9 +24,7 @@ func (tr *taskRunner) buildConfigSecretObject(
 		ObjectMeta: k8smetav1.ObjectMeta{
 			Name:      tr.configsSecretName(),
 			Namespace: tr.objectNamespace(),
-			Labels: map[string]string{
-				labelKeyTaskName: tr.taskConfig.Name,
-			},
+			Labels:    tr.objectLabels(),
 		},
 		Type:       "Opaque",
 		StringData: stringData,
@@ -34,48 +33,120 @@ func (tr *taskRunner) buildConfigSecretObject(
 	return rv, nil
 }
 
-func (tr *taskRunner) resolveSourceFileContent(
-	ctx context.Context,
-	source string,
-) (string, error) {
-	absSourceFilePath, err := filepath.Abs(filepath.Join(tr.sourceBaseDir, source))
+// resolveScriptFiles expands the files of the task config to the files to mount, adds the local
+// dependencies of the script or the k6 archive to run, vendors the remote modules if enabled,
+// and assigns their keys.
+func (tr *taskRunner) resolveScriptFiles(ctx context.Context) ([]scriptFile, error) {
+	files, err := resolveFileMounts(tr.sourceBaseDir, tr.taskConfig.Files)
 	if err != nil {
-		return "", fmt.Errorf("invalid source file %q: %w", source, err)
+		return nil, err
 	}
 
-	if !isChildPath(tr.sourceBaseDir, absSourceFilePath) {
-		return "", fmt.Errorf("invalid source file %q: not a child of %q", source, tr.sourceBaseDir)
+	if isK6Archive(tr.script) {
+		files, err = tr.addArchiveFile(files)
+	} else {
+		files, err = tr.addScriptDependencies(files)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// remote modules are bundled in k6 archives already
+	if tr.vendor.Enabled && !isK6Archive(tr.script) {
+		files, err = tr.vendorRemoteModules(ctx, files)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	assignScriptFileKeys(files)
+
+	return files, nil
+}
+
+// addScriptDependencies adds the local dependencies of the script which are not listed in the files.
+func (tr *taskRunner) addScriptDependencies(files []scriptFile) ([]scriptFile, error) {
+	deps, warnings, err := discoverScriptDependencies(tr.sourceBaseDir, files, tr.script)
+	if err != nil {
+		return nil, fmt.Errorf("discover dependencies of %q: %w", tr.script, err)
+	}
+	for _, warning := range warnings {
+		if _, err := fmt.Fprintf(os.Stderr, "Warning: %s\n", warning); err != nil {
+			return nil, err
+		}
+	}
+	for _, dep := range deps {
+		if !dep.Listed {
+			files = append(files, dep.scriptFile)
+		}
 	}
+	if tr.dependenciesOutput != nil {
+		if err := printScriptDependencies(tr.dependenciesOutput, tr.script, deps); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
 
-	b, err := os.ReadFile(absSourceFilePath)
+// addArchiveFile adds the k6 archive to run unless it's listed in the files.
+// The dependencies of the script are bundled in the archive.
+func (tr *taskRunner) addArchiveFile(files []scriptFile) ([]scriptFile, error) {
+	archive, err := resolveArchiveFile(tr.sourceBaseDir, tr.script, tr.archiveSource)
 	if err != nil {
-		return "", fmt.Errorf("failed to read source file %q: %w", source, err)
+		return nil, err
+	}
+	if tr.dependenciesOutput != nil {
+		if _, err := fmt.Fprintf(tr.dependenciesOutput, "Dependencies of %s are bundled in the k6 archive\n", tr.script); err != nil {
+			return nil, err
+		}
 	}
 
-	return string(b), nil
+	for _, f := range files {
+		if f.Path != archive.Path {
+			continue
+		}
+		if f.Source != archive.Source {
+			return nil, fmt.Errorf("k6 archive %q conflicts with %s mounted by files", archive.Path, f.Source)
+		}
+		return files, nil
+	}
+	return append(files, archive), nil
 }
 
+// buildScriptsConfigMapObject builds the ConfigMap of the shard of the script files, see planScriptsLayout.
 func (tr *taskRunner) buildScriptsConfigMapObject(
 	ctx context.Context,
+	shard int,
+	files []scriptFile,
 ) (*k8scorev1.ConfigMap, error) {
 	data := map[string]string{}
-	for _, file := range tr.taskConfig.Files {
-		sourceFileContent, err := tr.resolveSourceFileContent(ctx, file.Source)
+	binaryData := map[string][]byte{}
+	for _, file := range files {
+		b, err := file.read()
 		if err != nil {
-			return nil, fmt.Errorf("resolve %q: %w", file.Source, err)
+			return nil, err
+		}
+		// Data only holds UTF-8 strings, other content would be corrupted
+		if file.Binary || !utf8.Valid(b) {
+			binaryData[file.Key] = b
+		} else {
+			data[file.Key] = string(b)
 		}
-		data[file.Dest] = sourceFileContent
+	}
+	if len(binaryData) == 0 {
+		binaryData = nil
 	}
 
 	rv := &k8scorev1.ConfigMap{
 		ObjectMeta: k8smetav1.ObjectMeta{
-			Name:      tr.scriptsConfigMapName(),
+			Name:      tr.scriptsConfigMapName(shard),
 			Namespace: tr.objectNamespace(),
-			Labels: map[string]string{
-				labelKeyTaskName: tr.taskConfig.Name,
-			},
+			Labels:    tr.objectLabels(),
 		},
-		Data: data,
+		Data:       data,
+		BinaryData: binaryData,
 	}
 
 	return rv, nil
@@ -0,0 +1,196 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/k6ctl/internal/stdlib"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+// cronJobName returns the name of the CronJob created for the task.
+// Unlike the job, the CronJob is shared by all runs of the task.
+func cronJobName(taskName string) string {
+	return fmt.Sprintf("k6ctl-cronjob-%s", taskName)
+}
+
+func validateControllerKind(k6 K6) error {
+	switch k6.ControllerKind {
+	case "", controllerKindJob:
+		return nil
+	case controllerKindCronJob:
+		if k6.CronJob.Schedule == "" {
+			return fmt.Errorf("k6.cronJob.schedule is required for controllerKind %q", controllerKindCronJob)
+		}
+		switch k8sbatchv1.ConcurrencyPolicy(k6.CronJob.ConcurrencyPolicy) {
+		case "", k8sbatchv1.AllowConcurrent, k8sbatchv1.ForbidConcurrent, k8sbatchv1.ReplaceConcurrent:
+		default:
+			return fmt.Errorf(
+				"invalid k6.cronJob.concurrencyPolicy %q, expected one of %s, %s, %s",
+				k6.CronJob.ConcurrencyPolicy,
+				k8sbatchv1.AllowConcurrent, k8sbatchv1.ForbidConcurrent, k8sbatchv1.ReplaceConcurrent,
+			)
+		}
+		return nil
+	default:
+		return fmt.Errorf(
+			"invalid k6.controllerKind %q, expected %q or %q",
+			k6.ControllerKind, controllerKindJob, controllerKindCronJob,
+		)
+	}
+}
+
+func (tr *taskRunner) isCronJob() bool {
+	return tr.taskConfig.K6.ControllerKind == controllerKindCronJob
+}
+
+// buildCronJobObject wraps the job into a CronJob.
+func (tr *taskRunner) buildCronJobObject(job *k8sbatchv1.Job) *k8sbatchv1.CronJob {
+	settings := tr.taskConfig.K6.CronJob
+
+	concurrencyPolicy := k8sbatchv1.ConcurrencyPolicy(settings.ConcurrencyPolicy)
+	if concurrencyPolicy == "" {
+		// load tests should not overlap by default
+		concurrencyPolicy = k8sbatchv1.ForbidConcurrent
+	}
+
+	rv := &k8sbatchv1.CronJob{
+		ObjectMeta: k8smetav1.ObjectMeta{
+			Name:      cronJobName(tr.taskConfig.Name),
+			Namespace: tr.objectNamespace(),
+			Labels:    tr.objectLabels(),
+		},
+		Spec: k8sbatchv1.CronJobSpec{
+			Schedule:                   settings.Schedule,
+			ConcurrencyPolicy:          concurrencyPolicy,
+			Suspend:                    stdlib.Ptr(settings.Suspend),
+			SuccessfulJobsHistoryLimit: settings.SuccessfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     settings.FailedJobsHistoryLimit,
+			JobTemplate: k8sbatchv1.JobTemplateSpec{
+				ObjectMeta: k8smetav1.ObjectMeta{
+					Labels: tr.objectLabels(),
+				},
+				Spec: job.Spec,
+			},
+		},
+	}
+	if settings.TimeZone != "" {
+		rv.Spec.TimeZone = stdlib.Ptr(settings.TimeZone)
+	}
+
+	return rv
+}
+
+func (tr *taskRunner) applyCronJob(ctx context.Context, cronJob *k8sbatchv1.CronJob) error {
+	cronJobsClient := tr.kubeClient.BatchV1().CronJobs(tr.objectNamespace())
+	if _, err := createOrUpdateObject(ctx, cronJobsClient, cronJob); err != nil {
+		return fmt.Errorf("failed to create cron job %q: %w", cronJob.Name, err)
+	}
+
+	_, err := fmt.Fprintf(
+		os.Stderr,
+		"CronJob %s/%s is scheduled at %q. Use \"k6ctl trigger\" to start a run now.\n",
+		cronJob.Namespace, cronJob.Name, cronJob.Spec.Schedule,
+	)
+	return err
+}
+
+// TriggerTask starts an ad-hoc run of a task created with the CronJob controller kind.
+// The job is created from the CronJob's job template, and tracked the same way as RunTask.
+func TriggerTask(
+	ctx context.Context,
+	target target.Target,
+	taskRef TaskRef,
+	options ...RunTaskOption,
+) error {
+	opt := defaultRunTaskOption()
+	for _, o := range options {
+		if err := o.apply(opt); err != nil {
+			return err
+		}
+	}
+
+	if err := taskRef.validate(); err != nil {
+		return err
+	}
+
+	kubeconfig, ok := target.GetKubeconfig()
+	if !ok {
+		return fmt.Errorf("target does not have kubeconfig")
+	}
+	kubeClient, err := opt.KubeClientFactory(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	runID := opt.RunID
+	if runID == "" {
+		runID = NewRunID()
+	}
+
+	cronJob, err := kubeClient.BatchV1().CronJobs(taskRef.Namespace).Get(
+		ctx, cronJobName(taskRef.Name), k8smetav1.GetOptions{},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get cron job of task %s: %w", taskRef, err)
+	}
+
+	job := buildJobFromCronJob(cronJob, taskRef.Name, runID)
+	jobCreated, err := kubeClient.BatchV1().Jobs(taskRef.Namespace).Create(ctx, job, k8smetav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create job %q: %w", job.Name, err)
+	}
+
+	if _, err := fmt.Fprintf(
+		os.Stderr,
+		"Triggered task %s/%s with run ID %s (select objects with -l %s=%s)\n",
+		taskRef.Namespace, taskRef.Name, runID, labelKeyRunID, runID,
+	); err != nil {
+		return err
+	}
+
+	tr := &taskRunner{
+		target:            target,
+		runID:             runID,
+		kubeClient:        kubeClient,
+		followLogs:        opt.FollowLogs,
+		waitForCompletion: opt.WaitForCompletion,
+		waitInterval:      opt.WaitInterval,
+	}
+
+	return tr.trackJob(ctx, jobCreated)
+}
+
+// buildJobFromCronJob creates a manual job from the CronJob, similar to "kubectl create job --from".
+func buildJobFromCronJob(cronJob *k8sbatchv1.CronJob, taskName string, runID string) *k8sbatchv1.Job {
+	withRunID := func(labels map[string]string) map[string]string {
+		rv := map[string]string{}
+		for k, v := range labels {
+			rv[k] = v
+		}
+		rv[labelKeyRunID] = runID
+		return rv
+	}
+
+	spec := *cronJob.Spec.JobTemplate.Spec.DeepCopy()
+	spec.Template.Labels = withRunID(spec.Template.Labels)
+
+	return &k8sbatchv1.Job{
+		ObjectMeta: k8smetav1.ObjectMeta{
+			Name:      fmt.Sprintf("k6ctl-job-%s-%s", taskName, runID),
+			Namespace: cronJob.Namespace,
+			Labels:    withRunID(cronJob.Spec.JobTemplate.Labels),
+			Annotations: map[string]string{
+				annotationKeyCronJobInstantiate: "manual",
+			},
+			OwnerReferences: []k8smetav1.OwnerReference{
+				*k8smetav1.NewControllerRef(cronJob, k8sbatchv1.SchemeGroupVersion.WithKind("CronJob")),
+			},
+		},
+		Spec: spec,
+	}
+}
@@ -0,0 +1,244 @@
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/k6ctl/internal/config"
+	"github.com/Azure/k6ctl/internal/stdlib"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+func TestValidateControllerKind(t *testing.T) {
+	cases := []struct {
+		name      string
+		k6        K6
+		expectErr bool
+	}{
+		{name: "default", k6: K6{}},
+		{name: "job", k6: K6{ControllerKind: "Job"}},
+		{
+			name: "cron job",
+			k6: K6{
+				ControllerKind: "CronJob",
+				CronJob:        K6CronJob{Schedule: "0 2 * * *", ConcurrencyPolicy: "Replace"},
+			},
+		},
+		{name: "cron job without schedule", k6: K6{ControllerKind: "CronJob"}, expectErr: true},
+		{
+			name: "cron job with invalid concurrency policy",
+			k6: K6{
+				ControllerKind: "CronJob",
+				CronJob:        K6CronJob{Schedule: "0 2 * * *", ConcurrencyPolicy: "Sometimes"},
+			},
+			expectErr: true,
+		},
+		{name: "unknown kind", k6: K6{ControllerKind: "Deployment"}, expectErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateControllerKind(tc.k6)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRunTask_CronJob(t *testing.T) {
+	const namespace = "test"
+
+	taskConfig := &Schema{
+		Name: "nightly",
+		Files: []FileMount{
+			{
+				Source: "test.js",
+				Dest:   "test.js",
+			},
+		},
+		K6: K6{
+			Namespace:      namespace,
+			ControllerKind: "CronJob",
+			CronJob: K6CronJob{
+				Schedule:               "0 2 * * *",
+				FailedJobsHistoryLimit: stdlib.Ptr[int32](2),
+			},
+		},
+	}
+
+	ctx := context.Background()
+	kubeClient := fake.NewSimpleClientset()
+	fakeTarget := &target.StaticTarget{Kubeconfig: "/tmp/fake-kubeconfig"}
+	withFakeClient := applyRunTaskOptionFunc(func(option *runTaskOption) error {
+		option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+			return kubeClient, nil
+		}
+		return nil
+	})
+
+	for i := 0; i < 2; i++ {
+		// re-run updates the existing cron job
+		err := RunTask(
+			ctx,
+			fakeTarget,
+			config.NewRegistry().GetByName,
+			taskConfig,
+			"./testdata/integration",
+			"test.js",
+			withFakeClient,
+		)
+		assert.NoError(t, err)
+	}
+
+	jobs, err := kubeClient.BatchV1().Jobs(namespace).List(ctx, k8smetav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, jobs.Items)
+
+	cronJob, err := kubeClient.BatchV1().CronJobs(namespace).Get(ctx, "k6ctl-cronjob-nightly", k8smetav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "0 2 * * *", cronJob.Spec.Schedule)
+	assert.Equal(t, k8sbatchv1.ForbidConcurrent, cronJob.Spec.ConcurrencyPolicy)
+	assert.Equal(t, int32(2), stdlib.ValOrZero(cronJob.Spec.FailedJobsHistoryLimit))
+	assert.Nil(t, cronJob.Spec.JobTemplate.Spec.Selector)
+	assert.Nil(t, cronJob.Spec.JobTemplate.Spec.TTLSecondsAfterFinished)
+	assert.Equal(
+		t,
+		"k6ctl-scripts-config-nightly",
+		cronJob.Spec.JobTemplate.Spec.Template.Spec.Volumes[0].ConfigMap.Name,
+	)
+
+	// objects shared by the scheduled runs don't carry the run ID of the k6ctl run creating them
+	taskLabels := map[string]string{labelKeyTaskName: "nightly"}
+	assert.Equal(t, taskLabels, cronJob.Labels)
+	assert.Equal(t, taskLabels, cronJob.Spec.JobTemplate.Labels)
+	assert.Equal(t, taskLabels, cronJob.Spec.JobTemplate.Spec.Template.Labels)
+	configMap, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, "k6ctl-scripts-config-nightly", k8smetav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, taskLabels, configMap.Labels)
+
+	err = TriggerTask(
+		ctx,
+		fakeTarget,
+		TaskRef{Namespace: namespace, Name: "nightly"},
+		withFakeClient,
+		WithRunID("manual1"),
+		WithFollowLogs(false),
+		WithWaitForCompletion(false),
+	)
+	assert.NoError(t, err)
+
+	job, err := kubeClient.BatchV1().Jobs(namespace).Get(ctx, "k6ctl-job-nightly-manual1", k8smetav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "manual1", job.Labels[labelKeyRunID])
+	assert.Equal(t, "manual1", job.Spec.Template.Labels[labelKeyRunID])
+	assert.Equal(t, "manual", job.Annotations[annotationKeyCronJobInstantiate])
+	assert.Equal(t, cronJob.Name, k8smetav1.GetControllerOf(job).Name)
+
+	err = TriggerTask(
+		ctx,
+		fakeTarget,
+		TaskRef{Namespace: namespace, Name: "missing"},
+		withFakeClient,
+	)
+	assert.Error(t, err)
+}
+
+func TestScheduledRuns(t *testing.T) {
+	const namespace = "test"
+
+	taskLabels := map[string]string{labelKeyTaskName: "nightly"}
+	scheduledJob := func(name string) (*k8sbatchv1.Job, *k8scorev1.Pod) {
+		job := &k8sbatchv1.Job{
+			ObjectMeta: k8smetav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    taskLabels,
+				UID:       "uid-" + types.UID(name),
+			},
+		}
+		pod := &k8scorev1.Pod{
+			ObjectMeta: k8smetav1.ObjectMeta{
+				Name:      name + "-abcde",
+				Namespace: namespace,
+				Labels:    map[string]string{labelKeyTaskName: "nightly", labelKeyJobName: name},
+				OwnerReferences: []k8smetav1.OwnerReference{
+					{Kind: "Job", Name: job.Name, UID: job.UID, Controller: stdlib.Ptr(true)},
+				},
+			},
+		}
+		return job, pod
+	}
+	job1, pod1 := scheduledJob("k6ctl-cronjob-nightly-100")
+	job2, pod2 := scheduledJob("k6ctl-cronjob-nightly-200")
+
+	kubeClient := fake.NewSimpleClientset(
+		job1, pod1, job2, pod2,
+		&k8sbatchv1.CronJob{ObjectMeta: k8smetav1.ObjectMeta{Name: "k6ctl-cronjob-nightly", Namespace: namespace, Labels: taskLabels}},
+		&k8scorev1.ConfigMap{ObjectMeta: k8smetav1.ObjectMeta{Name: "k6ctl-scripts-config-nightly", Namespace: namespace, Labels: taskLabels}},
+	)
+	fakeTarget := &target.StaticTarget{Kubeconfig: "/tmp/fake-kubeconfig"}
+	ctx := context.Background()
+	runRef := TaskRef{Namespace: namespace, Name: "nightly", RunID: job1.Name}
+
+	status, err := GetTaskStatus(
+		ctx, fakeTarget, TaskRef{Namespace: namespace, Name: "nightly"},
+		applyGetTaskStatusOptionFunc(func(option *getTaskStatusOption) error {
+			option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+				return kubeClient, nil
+			}
+			return nil
+		}),
+	)
+	assert.NoError(t, err)
+	if assert.Len(t, status.Jobs, 2) {
+		assert.ElementsMatch(t, []string{job1.Name, job2.Name}, []string{status.Jobs[0].RunID, status.Jobs[1].RunID})
+	}
+
+	status, err = GetTaskStatus(
+		ctx, fakeTarget, runRef,
+		applyGetTaskStatusOptionFunc(func(option *getTaskStatusOption) error {
+			option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+				return kubeClient, nil
+			}
+			return nil
+		}),
+	)
+	assert.NoError(t, err)
+	if assert.Len(t, status.Jobs, 1) {
+		assert.Equal(t, job1.Name, status.Jobs[0].RunID)
+		assert.Len(t, status.Jobs[0].Pods, 1)
+		assert.Equal(t, pod1.Name, status.Jobs[0].Pods[0].Name)
+	}
+
+	// only the job and pods of the scheduled run are deleted, the shared objects are kept
+	deleted, err := DeleteTask(
+		ctx, fakeTarget, runRef,
+		applyDeleteTaskOptionFunc(func(option *deleteTaskOption) error {
+			option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+				return kubeClient, nil
+			}
+			return nil
+		}),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []k8scorev1.ObjectReference{
+		{Kind: "Job", Namespace: namespace, Name: job1.Name},
+		{Kind: "Pod", Namespace: namespace, Name: pod1.Name},
+	}, deleted)
+
+	_, err = kubeClient.BatchV1().Jobs(namespace).Get(ctx, job2.Name, k8smetav1.GetOptions{})
+	assert.NoError(t, err)
+	_, err = kubeClient.BatchV1().CronJobs(namespace).Get(ctx, "k6ctl-cronjob-nightly", k8smetav1.GetOptions{})
+	assert.NoError(t, err)
+}
@@ -4,17 +4,18 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path"
 
 	k8sbatchv1 "k8s.io/api/batch/v1"
 	k8scorev1 "k8s.io/api/core/v1"
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/Azure/k6ctl/internal/kubelib"
 	"github.com/Azure/k6ctl/internal/stdlib"
 )
 
-func (tr *taskRunner) buildJobObject() (*k8sbatchv1.Job, error) {
-	taskName := tr.taskConfig.Name
+func (tr *taskRunner) buildJobObject(layout scriptsLayout) (*k8sbatchv1.Job, error) {
 	k6RunnerImage := tr.taskConfig.K6.PodImage
 	scriptToRun := tr.script
 
@@ -30,94 +31,132 @@ func (tr *taskRunner) buildJobObject() (*k8sbatchv1.Job, error) {
 	}
 
 	var volumes []k8scorev1.Volume
-	if len(tr.taskConfig.Files) > 0 {
+	args := []string{"run", scriptToRun}
+	volumeMounts := []k8scorev1.VolumeMount{
+		{
+			Name:      scriptsVolumeName,
+			MountPath: containerScriptsPath,
+		},
+	}
+	var env []k8scorev1.EnvVar
+	if tr.useExecutionSegments() {
+		// each pod of the indexed job runs the execution segment of its completion index
+		env = append(env, k8scorev1.EnvVar{
+			Name: envKeyInstanceIndex,
+			ValueFrom: &k8scorev1.EnvVarSource{
+				FieldRef: &k8scorev1.ObjectFieldSelector{
+					FieldPath: fmt.Sprintf("metadata.annotations['%s']", k8sbatchv1.JobCompletionIndexAnnotation),
+				},
+			},
+		})
+		args = append(
+			args,
+			"--config", path.Join(containerSegmentsPath, segmentConfigFileName(fmt.Sprintf("$(%s)", envKeyInstanceIndex))),
+		)
+		volumeMounts = append(volumeMounts, k8scorev1.VolumeMount{
+			Name:      segmentsVolumeName,
+			MountPath: containerSegmentsPath,
+			ReadOnly:  true,
+		})
 		volumes = append(volumes, k8scorev1.Volume{
-			Name: scriptsVolumeName,
+			Name: segmentsVolumeName,
 			VolumeSource: k8scorev1.VolumeSource{
 				ConfigMap: &k8scorev1.ConfigMapVolumeSource{
 					LocalObjectReference: k8scorev1.LocalObjectReference{
-						Name: tr.scriptsConfigMapName(),
+						Name: tr.segmentsConfigMapName(),
 					},
 				},
 			},
 		})
 	}
 
+	// pods of the same run should be spread across the cluster as configured by k6.placement.
+	// Runs of a CronJob share the same pod template, so the task name is used.
+	instancesLabelKey, instancesLabelValue := labelKeyRunID, tr.runID
+	if tr.isCronJob() {
+		instancesLabelKey, instancesLabelValue = labelKeyTaskName, tr.taskConfig.Name
+	}
+
 	rv := &k8sbatchv1.Job{
 		ObjectMeta: k8smetav1.ObjectMeta{
 			Name:      tr.taskJobName(),
 			Namespace: tr.objectNamespace(),
-			Labels: map[string]string{
-				labelKeyTaskName: taskName,
-			},
+			Labels:    tr.objectLabels(),
 		},
 		Spec: k8sbatchv1.JobSpec{
-			TTLSecondsAfterFinished: stdlib.Ptr[int32](100),
-			Parallelism:             stdlib.Ptr[int32](tr.instances), // TODO: configurable
-			Completions:             stdlib.Ptr[int32](tr.instances), // TODO: configurable
-			ManualSelector:          stdlib.Ptr(true),
-			Selector: &k8smetav1.LabelSelector{
-				MatchLabels: map[string]string{
-					labelKeyTaskName: taskName,
-				},
-			},
+			Parallelism: stdlib.Ptr[int32](tr.instances), // TODO: configurable
+			Completions: stdlib.Ptr[int32](tr.instances), // TODO: configurable
+			// a failed instance is not retried: a retry would run the load again, and a failed
+			// threshold (exit code 99) is expected to fail the run. Overridable by k6.jobSpec.
+			BackoffLimit: stdlib.Ptr[int32](0),
 			Template: k8scorev1.PodTemplateSpec{
 				ObjectMeta: k8smetav1.ObjectMeta{
-					Labels: map[string]string{
-						labelKeyTaskName: taskName,
-					},
+					Labels: tr.objectLabels(),
 				},
 				Spec: k8scorev1.PodSpec{
 					Containers: []k8scorev1.Container{
 						{
-							Name:  containerNameRunner,
-							Image: k6RunnerImage,
-							Args:  []string{"run", scriptToRun},
-							VolumeMounts: []k8scorev1.VolumeMount{
-								{
-									Name:      scriptsVolumeName,
-									MountPath: containerScriptsPath,
-								},
-							},
-							EnvFrom:    envFrom,
-							WorkingDir: containerScriptsPath,
+							Name:         containerNameRunner,
+							Image:        k6RunnerImage,
+							Args:         args,
+							VolumeMounts: volumeMounts,
+							Env:          env,
+							EnvFrom:      envFrom,
+							WorkingDir:   containerScriptsPath,
 						},
 					},
 					RestartPolicy: "Never",
 					Volumes:       volumes,
-					Affinity: &k8scorev1.Affinity{
-						PodAntiAffinity: &k8scorev1.PodAntiAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []k8scorev1.PodAffinityTerm{
-								{
-									LabelSelector: &k8smetav1.LabelSelector{
-										MatchExpressions: []k8smetav1.LabelSelectorRequirement{
-											{
-												Key:      labelKeyTaskName,
-												Operator: "In",
-												Values:   []string{taskName},
-											},
-										},
-									},
-									TopologyKey: "kubernetes.io/hostname",
-								},
-							},
-						},
-					},
 				},
 			},
 		},
 	}
 
+	tr.applyScriptsLayoutToPodSpec(&rv.Spec.Template.Spec, layout)
+	applyPlacement(&rv.Spec.Template.Spec, tr.taskConfig.K6.Placement, &k8smetav1.LabelSelector{
+		MatchExpressions: []k8smetav1.LabelSelectorRequirement{
+			{
+				Key:      instancesLabelKey,
+				Operator: k8smetav1.LabelSelectorOpIn,
+				Values:   []string{instancesLabelValue},
+			},
+		},
+	})
+	if tr.startBarrier {
+		applyStartBarrierToContainer(&rv.Spec.Template.Spec.Containers[0])
+	}
+	if tr.summary {
+		// applied last, so the summary export is added after all the k6 args
+		applySummaryToContainer(&rv.Spec.Template.Spec, &rv.Spec.Template.Spec.Containers[0])
+	}
+	if tr.useExecutionSegments() {
+		rv.Spec.CompletionMode = stdlib.Ptr(k8sbatchv1.IndexedCompletion)
+	}
+	if !tr.isCronJob() {
+		// jobs created by a CronJob are selected by the controller generated selector,
+		// and cleaned up by the history limits
+		rv.Spec.TTLSecondsAfterFinished = stdlib.Ptr[int32](100)
+		rv.Spec.ManualSelector = stdlib.Ptr(true)
+		rv.Spec.Selector = &k8smetav1.LabelSelector{
+			MatchLabels: tr.objectLabels(),
+		}
+	}
+
+	if err := applySpecOverrides(rv, tr.taskConfig.K6); err != nil {
+		return nil, err
+	}
+
 	return rv, nil
 }
 
 func (tr *taskRunner) followJobLogs(
 	ctx context.Context,
 	job *k8sbatchv1.Job,
+	stop <-chan struct{},
 ) error {
-	selector, err := k8smetav1.LabelSelectorAsSelector(job.Spec.Selector)
+	selector, err := jobPodsSelector(job)
 	if err != nil {
-		return fmt.Errorf("invalid job selector: %w", err)
+		return err
 	}
 
 	addPrefix := false
@@ -134,7 +173,23 @@ func (tr *taskRunner) followJobLogs(
 			Selector:  selector,
 			Container: containerNameRunner,
 			AddPrefix: addPrefix,
+			Stop:      stop,
+			SkipLine:  isSummaryLogLine,
 			Output:    os.Stderr,
 		},
 	)
 }
+
+// jobPodsSelector returns the selector of the job's pods.
+// Falls back to the pod template labels if the job selector is not set yet.
+func jobPodsSelector(job *k8sbatchv1.Job) (labels.Selector, error) {
+	if job.Spec.Selector == nil {
+		return labels.SelectorFromSet(job.Spec.Template.Labels), nil
+	}
+
+	selector, err := k8smetav1.LabelSelectorAsSelector(job.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job selector: %w", err)
+	}
+	return selector, nil
+}
@@ -1,13 +1,79 @@
 package task
 
-import "github.com/Azure/k6ctl/internal/kubelib"
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/Azure/k6ctl/internal/kubelib"
+)
 
 type runTaskOption struct {
+	// RunID specifies the ID of the run. It's embedded in the object names and labels.
+	// If not provided, a random ID is generated.
+	RunID string
 	// Instances specifies the number of instances to run.
 	// Defaults to 1.
 	Instances int32
+	// Distribution specifies how the load is distributed across instances.
+	// If not provided, the distribution from the task config is used.
+	Distribution string
 	// FollowLogs specifies whether to follow the logs of the task.
 	FollowLogs bool
+	// WaitForCompletion specifies whether to wait for the task job to finish
+	// and report the failed pods as error.
+	WaitForCompletion bool
+	// WaitInterval specifies the interval for checking the task job status.
+	WaitInterval time.Duration
+	// StartBarrier specifies whether to start all instances together.
+	// If not enabled, the start barrier setting from the task config is used.
+	StartBarrier bool
+	// StartBarrierTimeout specifies how long to wait for all instances to be ready.
+	// If not provided, the timeout from the task config is used.
+	StartBarrierTimeout time.Duration
+	// ResumeK6Instance resumes the paused k6 instance when the start barrier is reached.
+	// If not provided, the k6 REST API of the pod is called.
+	// Unit test can provide a mock implementation.
+	ResumeK6Instance ResumeK6Instance
+	// UploadK6Assets uploads the files too large for ConfigMaps to the pods.
+	// If not provided, the files are streamed to the pods with exec.
+	// Unit test can provide a mock implementation.
+	UploadK6Assets UploadK6Assets
+	// Summary specifies whether to collect the end-of-test summaries of the instances.
+	// If not enabled, the summary setting from the task config is used.
+	Summary bool
+	// SummaryFile specifies the local file to save the merged summary to.
+	// If not provided, the file from the task config is used.
+	SummaryFile string
+	// CollectK6Summary reads the exported summary of a finished instance.
+	// If not provided, the summary is read from the pod logs.
+	// Unit test can provide a mock implementation.
+	CollectK6Summary CollectK6Summary
+	// DryRunOutput specifies the writer to render the objects to instead of running the task.
+	DryRunOutput io.Writer
+	// ShowSecrets specifies whether to render the secret values instead of redacting them.
+	ShowSecrets bool
+	// BuildArchive specifies whether to bundle the script into a k6 archive with the local k6 binary and run the archive.
+	BuildArchive bool
+	// K6Binary specifies the local k6 binary to build the archive with. Defaults to k6 on PATH.
+	K6Binary string
+	// BuildK6Archive builds the k6 archive of the script.
+	// If not provided, k6 archive is run with K6Binary.
+	// Unit test can provide a mock implementation.
+	BuildK6Archive BuildK6Archive
+	// Vendor specifies whether to vendor the remote modules imported by the scripts.
+	// If not enabled, the vendor setting from the task config is used.
+	Vendor bool
+	// FetchRemoteModule downloads the remote modules to vendor.
+	// If not provided, the modules are downloaded with HTTP GET.
+	// Unit test can provide a mock implementation.
+	FetchRemoteModule FetchRemoteModule
+	// DependenciesOutput specifies the writer to list the local dependencies of the script to.
+	// If not provided, the dependencies are not listed.
+	DependenciesOutput io.Writer
 	// KubeClientFactory provides the kubernetes client to use for the task.
 	// If not provided, createKubeClientFromKubeConfig is used.
 	// Unit test can provide a mock implementation.
@@ -18,6 +84,13 @@ func defaultRunTaskOption() *runTaskOption {
 	return &runTaskOption{
 		Instances:         1,
 		FollowLogs:        true,
+		WaitForCompletion: true,
+		WaitInterval:      5 * time.Second,
+		ResumeK6Instance:  resumeK6InstanceViaAPI,
+		CollectK6Summary:  collectK6SummaryFromLogs,
+		K6Binary:          defaultK6Binary,
+		BuildK6Archive:    buildK6ArchiveWithBinary,
+		FetchRemoteModule: fetchRemoteModuleHTTP,
 		KubeClientFactory: kubelib.CreateKubeClientFromKubeConfig,
 	}
 }
@@ -48,3 +121,96 @@ func WithInstances(replicas int32) RunTaskOption {
 		return nil
 	})
 }
+
+// WithWaitForCompletion specifies whether to wait for the task job to finish.
+// When enabled, a ThresholdsFailedError or TaskFailedError is returned if any pod failed.
+func WithWaitForCompletion(wait bool) RunTaskOption {
+	return applyRunTaskOptionFunc(func(option *runTaskOption) error {
+		option.WaitForCompletion = wait
+		return nil
+	})
+}
+
+// WithRunID specifies the ID of the run.
+func WithRunID(runID string) RunTaskOption {
+	return applyRunTaskOptionFunc(func(option *runTaskOption) error {
+		if errs := validation.IsDNS1123Label(runID); len(errs) > 0 {
+			return fmt.Errorf("invalid run ID %q: %s", runID, strings.Join(errs, ", "))
+		}
+		option.RunID = runID
+		return nil
+	})
+}
+
+// WithDistribution specifies how the load is distributed across instances.
+func WithDistribution(distribution string) RunTaskOption {
+	return applyRunTaskOptionFunc(func(option *runTaskOption) error {
+		if err := validateDistribution(distribution); err != nil {
+			return err
+		}
+		option.Distribution = distribution
+		return nil
+	})
+}
+
+// WithStartBarrier specifies whether to start all instances together,
+// and how long to wait for all instances to be ready. A zero timeout uses the task config value.
+func WithStartBarrier(enabled bool, timeout time.Duration) RunTaskOption {
+	return applyRunTaskOptionFunc(func(option *runTaskOption) error {
+		if timeout < 0 {
+			return fmt.Errorf("invalid start barrier timeout %s", timeout)
+		}
+		option.StartBarrier = enabled
+		option.StartBarrierTimeout = timeout
+		return nil
+	})
+}
+
+// WithSummary specifies whether to collect the end-of-test summaries of the instances,
+// and the local file to save the merged summary to. An empty file uses the task config value.
+func WithSummary(enabled bool, file string) RunTaskOption {
+	return applyRunTaskOptionFunc(func(option *runTaskOption) error {
+		option.Summary = enabled
+		option.SummaryFile = file
+		return nil
+	})
+}
+
+// WithDryRun specifies to render the objects of the run as multi-document YAML to w
+// instead of creating them. Secret values are redacted unless showSecrets is true,
+// and the config providers are only resolved when showSecrets is true.
+func WithDryRun(w io.Writer, showSecrets bool) RunTaskOption {
+	return applyRunTaskOptionFunc(func(option *runTaskOption) error {
+		option.DryRunOutput = w
+		option.ShowSecrets = showSecrets
+		return nil
+	})
+}
+
+// WithShowDependencies specifies to list the local dependencies of the script mounted with the run to w.
+func WithShowDependencies(w io.Writer) RunTaskOption {
+	return applyRunTaskOptionFunc(func(option *runTaskOption) error {
+		option.DependenciesOutput = w
+		return nil
+	})
+}
+
+// WithArchive specifies to bundle the script with its local and remote dependencies into a k6 archive
+// with the local k6 binary, and run the archive. An empty k6Binary uses k6 on PATH.
+func WithArchive(k6Binary string) RunTaskOption {
+	return applyRunTaskOptionFunc(func(option *runTaskOption) error {
+		option.BuildArchive = true
+		if k6Binary != "" {
+			option.K6Binary = k6Binary
+		}
+		return nil
+	})
+}
+
+// WithVendor specifies whether to vendor the remote modules imported by the scripts.
+func WithVendor(enabled bool) RunTaskOption {
+	return applyRunTaskOptionFunc(func(option *runTaskOption) error {
+		option.Vendor = enabled
+		return nil
+	})
+}
@@ -0,0 +1,157 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/k6ctl/internal/kubelib"
+	"github.com/Azure/k6ctl/internal/stdlib"
+)
+
+const (
+	specPathJobSpec = "k6.jobSpec"
+	specPathPodSpec = "k6.podSpec"
+)
+
+// jobSpecManagedFields are the job spec fields managed by k6ctl which cannot be overridden.
+var jobSpecManagedFields = []struct {
+	field string
+	hint  string
+}{
+	{field: "manualSelector", hint: "the selector is managed by k6ctl"},
+	{field: "selector", hint: "the selector is managed by k6ctl"},
+	{field: "template", hint: "use k6.podSpec to override the pod spec"},
+}
+
+// runnerContainerManagedFields are the fields of the runner container managed by k6ctl which cannot be overridden.
+var runnerContainerManagedFields = []struct {
+	field string
+	hint  string
+}{
+	{field: "command", hint: "the command runs k6 with the summary export"},
+	{field: "args", hint: "the args carry the script, the execution segment and the start barrier"},
+	{field: "envFrom", hint: "the configs are injected from the configs secret, use configs instead"},
+}
+
+// managedVolumeNames are the volumes of the pod managed by k6ctl.
+var managedVolumeNames = []string{scriptsVolumeName, segmentsVolumeName, scriptsSourceVolumeName, summaryVolumeName}
+
+// managedMountPaths are the paths in the containers where k6ctl mounts its volumes.
+var managedMountPaths = []string{containerScriptsPath, containerSegmentsPath, containerScriptsSourcePath, containerSummaryPath}
+
+// managedInitContainerNames are the init containers of the pod managed by k6ctl.
+var managedInitContainerNames = []string{initContainerNameAssets}
+
+// validatePodSpecManagedFields checks that the k6.podSpec override doesn't replace the containers,
+// volumes and mounts the run depends on. The other containers and volumes can be added freely.
+func validatePodSpecManagedFields(podSpec map[string]any) []error {
+	var errs []error
+	notAllowed := func(path string, hint string) {
+		errs = append(errs, fmt.Errorf("%s.%s: not allowed, %s", specPathPodSpec, path, hint))
+	}
+	// items returns the items of the list field as objects. The invalid values are reported by
+	// ValidatePartialObject, they are returned as empty objects here.
+	items := func(obj map[string]any, field string) []map[string]any {
+		list, _ := obj[field].([]any)
+		rv := make([]map[string]any, len(list))
+		for idx, item := range list {
+			rv[idx], _ = item.(map[string]any)
+		}
+		return rv
+	}
+
+	for idx, container := range items(podSpec, "containers") {
+		if container["name"] != containerNameRunner {
+			continue
+		}
+		path := fmt.Sprintf("containers[%d]", idx)
+		for _, managed := range runnerContainerManagedFields {
+			if _, exists := container[managed.field]; exists {
+				notAllowed(path+"."+managed.field, managed.hint)
+			}
+		}
+		for mountIdx, mount := range items(container, "volumeMounts") {
+			name, _ := mount["name"].(string)
+			mountPath, _ := mount["mountPath"].(string)
+			switch {
+			case slices.Contains(managedVolumeNames, name):
+				notAllowed(fmt.Sprintf("%s.volumeMounts[%d].name", path, mountIdx), fmt.Sprintf("volume %q is managed by k6ctl", name))
+			case mountPath != "" && slices.ContainsFunc(managedMountPaths, func(p string) bool {
+				return stdlib.IsChildPath(p, mountPath)
+			}):
+				notAllowed(
+					fmt.Sprintf("%s.volumeMounts[%d].mountPath", path, mountIdx),
+					fmt.Sprintf("path %q is managed by k6ctl", mountPath),
+				)
+			}
+		}
+	}
+	for idx, volume := range items(podSpec, "volumes") {
+		if name, _ := volume["name"].(string); slices.Contains(managedVolumeNames, name) {
+			notAllowed(fmt.Sprintf("volumes[%d]", idx), fmt.Sprintf("volume %q is managed by k6ctl", name))
+		}
+	}
+	for idx, container := range items(podSpec, "initContainers") {
+		if name, _ := container["name"].(string); slices.Contains(managedInitContainerNames, name) {
+			notAllowed(fmt.Sprintf("initContainers[%d]", idx), fmt.Sprintf("init container %q is managed by k6ctl", name))
+		}
+	}
+
+	return errs
+}
+
+// validateSpecOverrides validates the k6.jobSpec and k6.podSpec overrides.
+func validateSpecOverrides(k6 K6) error {
+	var errs []error
+
+	if len(k6.JobSpec) > 0 {
+		for _, managed := range jobSpecManagedFields {
+			if _, exists := k6.JobSpec[managed.field]; exists {
+				errs = append(errs, fmt.Errorf("%s.%s: not allowed, %s", specPathJobSpec, managed.field, managed.hint))
+			}
+		}
+		if err := kubelib.ValidatePartialObject(specPathJobSpec, k6.JobSpec, k8sbatchv1.JobSpec{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(k6.PodSpec) > 0 {
+		errs = append(errs, validatePodSpecManagedFields(k6.PodSpec)...)
+		if err := kubelib.ValidatePartialObject(specPathPodSpec, k6.PodSpec, k8scorev1.PodSpec{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// applySpecOverrides strategic-merge-patches the k6.jobSpec and k6.podSpec overrides over the job.
+func applySpecOverrides(job *k8sbatchv1.Job, k6 K6) error {
+	if len(k6.JobSpec) < 1 && len(k6.PodSpec) < 1 {
+		return nil
+	}
+
+	if err := validateSpecOverrides(k6); err != nil {
+		return fmt.Errorf("invalid spec overrides: %w", err)
+	}
+
+	spec := map[string]any{}
+	for k, v := range k6.JobSpec {
+		spec[k] = v
+	}
+	if len(k6.PodSpec) > 0 {
+		spec["template"] = map[string]any{
+			"spec": k6.PodSpec,
+		}
+	}
+
+	if err := kubelib.StrategicMergeObject(job, map[string]any{"spec": spec}); err != nil {
+		return fmt.Errorf("failed to apply spec overrides: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package task
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/Azure/k6ctl/internal/stdlib"
+)
+
+func TestApplySpecOverrides(t *testing.T) {
+	loadK6 := func(t *testing.T, s string) K6 {
+		t.Helper()
+
+		schema, err := LoadSchema(strings.NewReader(s))
+		assert.NoError(t, err)
+		return schema.K6
+	}
+
+	newJob := func() *k8sbatchv1.Job {
+		return &k8sbatchv1.Job{
+			Spec: k8sbatchv1.JobSpec{
+				Parallelism:  stdlib.Ptr[int32](2),
+				BackoffLimit: stdlib.Ptr[int32](0),
+				Template: k8scorev1.PodTemplateSpec{
+					Spec: k8scorev1.PodSpec{
+						Containers: []k8scorev1.Container{
+							{Name: containerNameRunner, Image: "k6"},
+						},
+						RestartPolicy: "Never",
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("no overrides", func(t *testing.T) {
+		job := newJob()
+		assert.NoError(t, applySpecOverrides(job, K6{}))
+		assert.Equal(t, newJob(), job)
+	})
+
+	t.Run("merge overrides", func(t *testing.T) {
+		k6 := loadK6(t, `
+k6:
+  jobSpec:
+    backoffLimit: 2
+  podSpec:
+    serviceAccountName: k6-runner
+    nodeSelector:
+      agentpool: loadtest
+    tolerations:
+    - key: dedicated
+      operator: Equal
+      value: loadtest
+      effect: NoSchedule
+    imagePullSecrets:
+    - name: registry
+    containers:
+    - name: k6-runner
+      resources:
+        requests:
+          cpu: "2"
+          memory: 1Gi
+`)
+		job := newJob()
+		assert.NoError(t, applySpecOverrides(job, k6))
+
+		assert.Equal(t, int32(2), stdlib.ValOrZero(job.Spec.BackoffLimit))
+		assert.Equal(t, int32(2), stdlib.ValOrZero(job.Spec.Parallelism))
+
+		podSpec := job.Spec.Template.Spec
+		assert.Equal(t, "k6-runner", podSpec.ServiceAccountName)
+		assert.Equal(t, map[string]string{"agentpool": "loadtest"}, podSpec.NodeSelector)
+		assert.Len(t, podSpec.Tolerations, 1)
+		assert.Equal(t, []k8scorev1.LocalObjectReference{{Name: "registry"}}, podSpec.ImagePullSecrets)
+		assert.Equal(t, k8scorev1.RestartPolicy("Never"), podSpec.RestartPolicy)
+		assert.Len(t, podSpec.Containers, 1)
+		assert.Equal(t, "k6", podSpec.Containers[0].Image)
+		assert.Equal(
+			t,
+			resource.MustParse("1Gi"),
+			podSpec.Containers[0].Resources.Requests[k8scorev1.ResourceMemory],
+		)
+	})
+
+	t.Run("managed pod spec fields", func(t *testing.T) {
+		k6 := loadK6(t, `
+k6:
+  podSpec:
+    containers:
+    - name: k6-runner
+      command: ["k6"]
+      args: ["run", "other.js"]
+      envFrom:
+      - secretRef:
+          name: other
+      volumeMounts:
+      - name: ca-certs
+        mountPath: /etc/ssl/certs
+      - name: k6-scripts
+        mountPath: /other
+      - name: other
+        mountPath: /scripts/data
+    - name: sidecar
+      image: busybox
+      command: ["sleep"]
+    initContainers:
+    - name: k6ctl-assets
+      image: busybox
+    volumes:
+    - name: ca-certs
+      configMap:
+        name: ca-certs
+    - name: k6ctl-summary
+      emptyDir: {}
+`)
+		err := applySpecOverrides(newJob(), k6)
+		assert.Error(t, err)
+		for _, msg := range []string{
+			"k6.podSpec.containers[0].command: not allowed",
+			"k6.podSpec.containers[0].args: not allowed",
+			"k6.podSpec.containers[0].envFrom: not allowed",
+			`k6.podSpec.containers[0].volumeMounts[1].name: not allowed, volume "k6-scripts" is managed by k6ctl`,
+			`k6.podSpec.containers[0].volumeMounts[2].mountPath: not allowed, path "/scripts/data" is managed by k6ctl`,
+			`k6.podSpec.initContainers[0]: not allowed, init container "k6ctl-assets" is managed by k6ctl`,
+			`k6.podSpec.volumes[1]: not allowed, volume "k6ctl-summary" is managed by k6ctl`,
+		} {
+			assert.ErrorContains(t, err, msg)
+		}
+		// other containers, volumes and mounts can be added
+		for _, path := range []string{"containers[0].volumeMounts[0]", "containers[1]", "volumes[0]"} {
+			assert.NotContains(t, err.Error(), "k6.podSpec."+path+":")
+			assert.NotContains(t, err.Error(), "k6.podSpec."+path+".")
+		}
+	})
+
+	t.Run("invalid overrides", func(t *testing.T) {
+		k6 := loadK6(t, `
+k6:
+  jobSpec:
+    backoffLimit: "abc"
+    template: {}
+  podSpec:
+    nodeSelector: foo
+    containers:
+    - name: k6-runner
+      resource: {}
+      resources:
+        limits:
+          cpu: "not-a-quantity"
+`)
+		err := applySpecOverrides(newJob(), k6)
+		assert.Error(t, err)
+		for _, path := range []string{
+			"k6.jobSpec.backoffLimit: expected integer",
+			"k6.jobSpec.template: not allowed",
+			"k6.podSpec.nodeSelector: expected object",
+			"k6.podSpec.containers[0].resource: unknown field",
+			"k6.podSpec.containers[0].resources.limits.cpu:",
+		} {
+			assert.ErrorContains(t, err, path)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func validatePlacement(placement K6Placement) error {
+	switch placement.Mode {
+	case "", placementModeRequired, placementModePreferred, placementModeSpread, placementModeNone:
+	default:
+		return fmt.Errorf(
+			"invalid k6.placement.mode %q, expected one of %q, %q, %q or %q",
+			placement.Mode, placementModeRequired, placementModePreferred, placementModeSpread, placementModeNone,
+		)
+	}
+
+	if placement.MaxSkew < 0 {
+		return fmt.Errorf("invalid k6.placement.maxSkew %d, expected a positive number", placement.MaxSkew)
+	}
+
+	switch k8scorev1.UnsatisfiableConstraintAction(placement.WhenUnsatisfiable) {
+	case "", k8scorev1.DoNotSchedule, k8scorev1.ScheduleAnyway:
+	default:
+		return fmt.Errorf(
+			"invalid k6.placement.whenUnsatisfiable %q, expected %q or %q",
+			placement.WhenUnsatisfiable, k8scorev1.DoNotSchedule, k8scorev1.ScheduleAnyway,
+		)
+	}
+
+	return nil
+}
+
+func placementMode(placement K6Placement) string {
+	if placement.Mode == "" {
+		return placementModeRequired
+	}
+	return placement.Mode
+}
+
+func placementTopologyKey(placement K6Placement) string {
+	if placement.TopologyKey == "" {
+		return defaultPlacementTopologyKey
+	}
+	return placement.TopologyKey
+}
+
+// applyPlacement sets the scheduling constraints of the instances selected by instancesSelector.
+func applyPlacement(
+	podSpec *k8scorev1.PodSpec,
+	placement K6Placement,
+	instancesSelector *k8smetav1.LabelSelector,
+) {
+	topologyKey := placementTopologyKey(placement)
+	affinityTerm := k8scorev1.PodAffinityTerm{
+		LabelSelector: instancesSelector,
+		TopologyKey:   topologyKey,
+	}
+
+	switch placementMode(placement) {
+	case placementModeRequired:
+		podSpec.Affinity = &k8scorev1.Affinity{
+			PodAntiAffinity: &k8scorev1.PodAntiAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []k8scorev1.PodAffinityTerm{affinityTerm},
+			},
+		}
+	case placementModePreferred:
+		podSpec.Affinity = &k8scorev1.Affinity{
+			PodAntiAffinity: &k8scorev1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []k8scorev1.WeightedPodAffinityTerm{
+					{Weight: 100, PodAffinityTerm: affinityTerm},
+				},
+			},
+		}
+	case placementModeSpread:
+		maxSkew := placement.MaxSkew
+		if maxSkew == 0 {
+			maxSkew = 1
+		}
+		whenUnsatisfiable := k8scorev1.UnsatisfiableConstraintAction(placement.WhenUnsatisfiable)
+		if whenUnsatisfiable == "" {
+			whenUnsatisfiable = k8scorev1.DoNotSchedule
+		}
+		podSpec.TopologySpreadConstraints = []k8scorev1.TopologySpreadConstraint{
+			{
+				MaxSkew:           maxSkew,
+				TopologyKey:       topologyKey,
+				WhenUnsatisfiable: whenUnsatisfiable,
+				LabelSelector:     instancesSelector,
+			},
+		}
+	case placementModeNone:
+		// no constraint
+	}
+}
+
+// countSchedulableTopologyDomains counts the topology domains with nodes the pod can be scheduled to.
+// Only the node conditions, node selector and taints are considered.
+func countSchedulableTopologyDomains(nodes []k8scorev1.Node, podSpec *k8scorev1.PodSpec, topologyKey string) int {
+	nodeSelector := labels.SelectorFromSet(podSpec.NodeSelector)
+
+	domains := map[string]struct{}{}
+	for _, node := range nodes {
+		if node.Spec.Unschedulable || !isNodeReady(&node) {
+			continue
+		}
+		if !nodeSelector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		if !toleratesNodeTaints(podSpec.Tolerations, node.Spec.Taints) {
+			continue
+		}
+		domain, ok := node.Labels[topologyKey]
+		if !ok {
+			continue
+		}
+		domains[domain] = struct{}{}
+	}
+
+	return len(domains)
+}
+
+func isNodeReady(node *k8scorev1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == k8scorev1.NodeReady {
+			return c.Status == k8scorev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func toleratesNodeTaints(tolerations []k8scorev1.Toleration, taints []k8scorev1.Taint) bool {
+	for _, taint := range taints {
+		if taint.Effect == k8scorev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for _, toleration := range tolerations {
+			if toleration.ToleratesTaint(&taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+// checkPlacement warns when the cluster does not have enough schedulable nodes
+// to place each instance in a different topology domain with required anti-affinity.
+func (tr *taskRunner) checkPlacement(ctx context.Context, w io.Writer, podSpec *k8scorev1.PodSpec) error {
+	placement := tr.taskConfig.K6.Placement
+	if placementMode(placement) != placementModeRequired || tr.instances <= 1 {
+		return nil
+	}
+	topologyKey := placementTopologyKey(placement)
+
+	nodes, err := tr.kubeClient.CoreV1().Nodes().List(ctx, k8smetav1.ListOptions{})
+	if err != nil {
+		// listing nodes is not always permitted, the check is best-effort
+		_, err := fmt.Fprintf(w, "Warning: skipped checking schedulable nodes: %s\n", err)
+		return err
+	}
+
+	domains := countSchedulableTopologyDomains(nodes.Items, podSpec, topologyKey)
+	if int32(domains) >= tr.instances {
+		return nil
+	}
+
+	_, err = fmt.Fprintf(
+		w,
+		"Warning: %d instance(s) require distinct %q, but only %d schedulable domain(s) found. "+
+			"Extra instances will stay pending. Consider setting k6.placement.mode to %q or %q\n",
+		tr.instances, topologyKey, domains, placementModePreferred, placementModeSpread,
+	)
+	return err
+}
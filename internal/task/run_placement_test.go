@@ -0,0 +1,162 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidatePlacement(t *testing.T) {
+	assert.NoError(t, validatePlacement(K6Placement{}))
+	assert.NoError(t, validatePlacement(K6Placement{Mode: placementModeSpread, MaxSkew: 2, WhenUnsatisfiable: "ScheduleAnyway"}))
+	assert.Error(t, validatePlacement(K6Placement{Mode: "random"}))
+	assert.Error(t, validatePlacement(K6Placement{MaxSkew: -1}))
+	assert.Error(t, validatePlacement(K6Placement{WhenUnsatisfiable: "Never"}))
+}
+
+func TestApplyPlacement(t *testing.T) {
+	selector := &k8smetav1.LabelSelector{
+		MatchLabels: map[string]string{labelKeyRunID: "run"},
+	}
+
+	t.Run("required by default", func(t *testing.T) {
+		podSpec := &k8scorev1.PodSpec{}
+		applyPlacement(podSpec, K6Placement{}, selector)
+
+		terms := podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+		if assert.Len(t, terms, 1) {
+			assert.Equal(t, defaultPlacementTopologyKey, terms[0].TopologyKey)
+			assert.Equal(t, selector, terms[0].LabelSelector)
+		}
+		assert.Empty(t, podSpec.TopologySpreadConstraints)
+	})
+
+	t.Run("preferred", func(t *testing.T) {
+		podSpec := &k8scorev1.PodSpec{}
+		applyPlacement(podSpec, K6Placement{Mode: placementModePreferred, TopologyKey: "topology.kubernetes.io/zone"}, selector)
+
+		assert.Empty(t, podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+		terms := podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		if assert.Len(t, terms, 1) {
+			assert.Equal(t, "topology.kubernetes.io/zone", terms[0].PodAffinityTerm.TopologyKey)
+		}
+	})
+
+	t.Run("spread", func(t *testing.T) {
+		podSpec := &k8scorev1.PodSpec{}
+		applyPlacement(podSpec, K6Placement{Mode: placementModeSpread}, selector)
+
+		assert.Nil(t, podSpec.Affinity)
+		assert.Equal(t, []k8scorev1.TopologySpreadConstraint{
+			{
+				MaxSkew:           1,
+				TopologyKey:       defaultPlacementTopologyKey,
+				WhenUnsatisfiable: k8scorev1.DoNotSchedule,
+				LabelSelector:     selector,
+			},
+		}, podSpec.TopologySpreadConstraints)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		podSpec := &k8scorev1.PodSpec{}
+		applyPlacement(podSpec, K6Placement{Mode: placementModeNone}, selector)
+
+		assert.Nil(t, podSpec.Affinity)
+		assert.Empty(t, podSpec.TopologySpreadConstraints)
+	})
+}
+
+func TestCheckPlacement(t *testing.T) {
+	node := func(name string, ready bool, mutate func(node *k8scorev1.Node)) *k8scorev1.Node {
+		status := k8scorev1.ConditionFalse
+		if ready {
+			status = k8scorev1.ConditionTrue
+		}
+		rv := &k8scorev1.Node{
+			ObjectMeta: k8smetav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{defaultPlacementTopologyKey: name},
+			},
+			Status: k8scorev1.NodeStatus{
+				Conditions: []k8scorev1.NodeCondition{{Type: k8scorev1.NodeReady, Status: status}},
+			},
+		}
+		if mutate != nil {
+			mutate(rv)
+		}
+		return rv
+	}
+
+	kubeClient := fake.NewSimpleClientset(
+		node("node-0", true, nil),
+		node("node-1", true, nil),
+		node("node-2", false, nil),
+		node("node-3", true, func(node *k8scorev1.Node) {
+			node.Spec.Unschedulable = true
+		}),
+		node("node-4", true, func(node *k8scorev1.Node) {
+			node.Spec.Taints = []k8scorev1.Taint{{Key: "dedicated", Value: "load", Effect: k8scorev1.TaintEffectNoSchedule}}
+		}),
+	)
+
+	cases := []struct {
+		name          string
+		placement     K6Placement
+		instances     int32
+		podSpec       k8scorev1.PodSpec
+		expectWarning bool
+	}{
+		{
+			name:      "enough nodes",
+			instances: 2,
+		},
+		{
+			name:          "not enough nodes",
+			instances:     3,
+			expectWarning: true,
+		},
+		{
+			name:      "tolerated taint",
+			instances: 3,
+			podSpec: k8scorev1.PodSpec{
+				Tolerations: []k8scorev1.Toleration{
+					{Key: "dedicated", Operator: k8scorev1.TolerationOpEqual, Value: "load", Effect: k8scorev1.TaintEffectNoSchedule},
+				},
+			},
+		},
+		{
+			name:          "node selector",
+			instances:     2,
+			podSpec:       k8scorev1.PodSpec{NodeSelector: map[string]string{defaultPlacementTopologyKey: "node-0"}},
+			expectWarning: true,
+		},
+		{
+			name:      "preferred placement",
+			placement: K6Placement{Mode: placementModePreferred},
+			instances: 10,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := &taskRunner{
+				kubeClient: kubeClient,
+				instances:  tc.instances,
+				taskConfig: &Schema{K6: K6{Placement: tc.placement}},
+			}
+
+			var out bytes.Buffer
+			assert.NoError(t, tr.checkPlacement(context.Background(), &out, &tc.podSpec))
+			if tc.expectWarning {
+				assert.Contains(t, out.String(), "Warning:")
+			} else {
+				assert.Empty(t, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func validateDistribution(distribution string) error {
+	switch distribution {
+	case "", DistributionReplicate, DistributionSegments:
+		return nil
+	default:
+		return fmt.Errorf(
+			"invalid distribution %q, expected %q or %q",
+			distribution, DistributionReplicate, DistributionSegments,
+		)
+	}
+}
+
+func (tr *taskRunner) useExecutionSegments() bool {
+	return tr.distribution == DistributionSegments
+}
+
+// executionSegments splits the test into equal k6 execution segments, one for each instance.
+// It returns the execution segment sequence and the segment of each instance.
+// ref: https://grafana.com/docs/k6/latest/using-k6/k6-options/reference/#execution-segment
+func executionSegments(instances int32) (string, []string) {
+	if instances < 1 {
+		instances = 1
+	}
+
+	points := make([]string, 0, instances+1)
+	for i := int32(0); i <= instances; i++ {
+		points = append(points, big.NewRat(int64(i), int64(instances)).RatString())
+	}
+
+	segments := make([]string, 0, instances)
+	for i := int32(0); i < instances; i++ {
+		segments = append(segments, fmt.Sprintf("%s:%s", points[i], points[i+1]))
+	}
+
+	return strings.Join(points, ","), segments
+}
+
+// segmentConfigFileName returns the name of the k6 config file for the instance with the given index.
+func segmentConfigFileName(index string) string {
+	return fmt.Sprintf("%s.json", index)
+}
+
+// k6SegmentConfig is the k6 config file content for running an execution segment.
+type k6SegmentConfig struct {
+	ExecutionSegment         string `json:"executionSegment"`
+	ExecutionSegmentSequence string `json:"executionSegmentSequence"`
+}
+
+// buildSegmentsConfigMapObject builds the config map holding a k6 config file for each instance.
+// Each pod of the indexed job picks the file by its completion index.
+func (tr *taskRunner) buildSegmentsConfigMapObject() (*k8scorev1.ConfigMap, error) {
+	sequence, segments := executionSegments(tr.instances)
+
+	data := map[string]string{}
+	for idx, segment := range segments {
+		b, err := json.Marshal(k6SegmentConfig{
+			ExecutionSegment:         segment,
+			ExecutionSegmentSequence: sequence,
+		})
+		if err != nil {
+			return nil, err
+		}
+		data[segmentConfigFileName(fmt.Sprint(idx))] = string(b)
+	}
+
+	rv := &k8scorev1.ConfigMap{
+		ObjectMeta: k8smetav1.ObjectMeta{
+			Name:      tr.segmentsConfigMapName(),
+			Namespace: tr.objectNamespace(),
+			Labels:    tr.objectLabels(),
+		},
+		Data: data,
+	}
+
+	return rv, nil
+}
+
+func (tr *taskRunner) segmentsConfigMapName() string {
+	return fmt.Sprintf("k6ctl-segments-config-%s", tr.sharedObjectNameSuffix())
+}
@@ -0,0 +1,111 @@
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/k6ctl/internal/config"
+	"github.com/Azure/k6ctl/internal/stdlib"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+func TestExecutionSegments(t *testing.T) {
+	cases := []struct {
+		instances        int32
+		expectedSequence string
+		expectedSegments []string
+	}{
+		{
+			instances:        1,
+			expectedSequence: "0,1",
+			expectedSegments: []string{"0:1"},
+		},
+		{
+			instances:        2,
+			expectedSequence: "0,1/2,1",
+			expectedSegments: []string{"0:1/2", "1/2:1"},
+		},
+		{
+			instances:        4,
+			expectedSequence: "0,1/4,1/2,3/4,1",
+			expectedSegments: []string{"0:1/4", "1/4:1/2", "1/2:3/4", "3/4:1"},
+		},
+	}
+
+	for _, tc := range cases {
+		sequence, segments := executionSegments(tc.instances)
+		assert.Equal(t, tc.expectedSequence, sequence)
+		assert.Equal(t, tc.expectedSegments, segments)
+	}
+}
+
+func TestRunTask_ExecutionSegments(t *testing.T) {
+	const namespace = "test"
+
+	taskConfig := &Schema{
+		Name: "test",
+		Files: []FileMount{
+			{
+				Source: "test.js",
+				Dest:   "test.js",
+			},
+		},
+		K6: K6{
+			Namespace: namespace,
+		},
+	}
+
+	ctx := context.Background()
+	kubeClient := fake.NewSimpleClientset()
+
+	err := RunTask(
+		ctx,
+		&target.StaticTarget{Kubeconfig: "/tmp/fake-kubeconfig"},
+		config.NewRegistry().GetByName,
+		taskConfig,
+		"./testdata/integration",
+		"test.js",
+		applyRunTaskOptionFunc(func(option *runTaskOption) error {
+			option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+				return kubeClient, nil
+			}
+			return nil
+		}),
+		WithRunID("run1"),
+		WithInstances(3),
+		WithDistribution(DistributionSegments),
+		WithFollowLogs(false),
+		WithWaitForCompletion(false),
+	)
+	assert.NoError(t, err)
+
+	job, err := kubeClient.BatchV1().Jobs(namespace).Get(ctx, "k6ctl-job-test-run1", k8smetav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, k8sbatchv1.IndexedCompletion, stdlib.ValOrZero(job.Spec.CompletionMode))
+	assert.Equal(t, int32(3), stdlib.ValOrZero(job.Spec.Completions))
+
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(
+		t,
+		[]string{"run", "test.js", "--config", "/k6ctl/segments/$(K6CTL_INSTANCE_INDEX).json"},
+		container.Args,
+	)
+	assert.Equal(t, envKeyInstanceIndex, container.Env[0].Name)
+
+	configMap, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, "k6ctl-segments-config-test-run1", k8smetav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, configMap.Data, 3)
+	assert.JSONEq(
+		t,
+		`{"executionSegment":"1/3:2/3","executionSegmentSequence":"0,1/3,2/3,1"}`,
+		configMap.Data["1.json"],
+	)
+
+	assert.Error(t, WithDistribution("round-robin").apply(defaultRunTaskOption()))
+}
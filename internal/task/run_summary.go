@@ -0,0 +1,212 @@
+package task
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/Azure/k6ctl/internal/stdlib"
+)
+
+// summaryLogMarker prefixes the log line carrying the exported summary of the instance.
+const summaryLogMarker = "K6CTL_SUMMARY "
+
+// summaryRunnerScript runs k6 with the given args, then prints the exported summary
+// as a single marked log line so it can be read back after the pod finished.
+// Signals are forwarded to k6 so the instance can be stopped gracefully.
+var summaryRunnerScript = fmt.Sprintf(`trap 'kill -TERM "$pid" 2>/dev/null' TERM INT
+k6 "$@" &
+pid=$!
+while :; do
+  wait "$pid"
+  rc=$?
+  # wait returns early when a signal is trapped, keep waiting until k6 exits
+  kill -0 "$pid" 2>/dev/null || break
+done
+if [ -f %[1]q ]; then
+  printf '\n%[2]s'
+  tr -d '\n' < %[1]q
+  printf '\n'
+fi
+exit "$rc"
+`, path.Join(containerSummaryPath, summaryFileName), summaryLogMarker)
+
+const summaryFileName = "summary.json"
+
+// errSummaryNotFound is returned when the instance did not export a summary,
+// for example when it failed before the test ended.
+var errSummaryNotFound = errors.New("no summary found in the logs")
+
+// CollectK6Summary reads the exported summary of the finished k6 instance in the pod.
+type CollectK6Summary func(ctx context.Context, kubeClient kubernetes.Interface, pod *k8scorev1.Pod) (*Summary, error)
+
+// collectK6SummaryFromLogs reads the summary line printed at the end of the runner container logs.
+func collectK6SummaryFromLogs(ctx context.Context, kubeClient kubernetes.Interface, pod *k8scorev1.Pod) (*Summary, error) {
+	logs, err := kubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &k8scorev1.PodLogOptions{
+		Container: containerNameRunner,
+		TailLines: stdlib.Ptr[int64](10),
+	}).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read logs of pod %q: %w", pod.Name, err)
+	}
+
+	return parseSummaryFromLogs(logs)
+}
+
+func isSummaryLogLine(line []byte) bool {
+	return bytes.HasPrefix(line, []byte(summaryLogMarker))
+}
+
+// parseSummaryFromLogs parses the last summary line in the logs.
+func parseSummaryFromLogs(logs []byte) (*Summary, error) {
+	var summaryLine []byte
+
+	scanner := bufio.NewScanner(bytes.NewReader(logs))
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Bytes(); isSummaryLogLine(line) {
+			summaryLine = bytes.Clone(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if summaryLine == nil {
+		return nil, errSummaryNotFound
+	}
+
+	rv := new(Summary)
+	if err := json.Unmarshal(bytes.TrimPrefix(summaryLine, []byte(summaryLogMarker)), rv); err != nil {
+		return nil, fmt.Errorf("parse summary: %w", err)
+	}
+	return rv, nil
+}
+
+// resolveSummaryFile resolves the local file to save the merged summary to.
+func resolveSummaryFile(opt *runTaskOption, k6 K6, taskName string, runID string) string {
+	switch {
+	case opt.SummaryFile != "":
+		return opt.SummaryFile
+	case k6.Summary.File != "":
+		return k6.Summary.File
+	default:
+		return fmt.Sprintf("k6ctl-summary-%s-%s.json", taskName, runID)
+	}
+}
+
+// applySummaryToContainer makes the k6 runner export its summary to a shared volume
+// and print it at the end of the logs.
+func applySummaryToContainer(podSpec *k8scorev1.PodSpec, container *k8scorev1.Container) {
+	container.Command = []string{"sh", "-c", summaryRunnerScript, "k6"}
+	container.Args = append(
+		container.Args,
+		"--summary-export", path.Join(containerSummaryPath, summaryFileName),
+	)
+	container.VolumeMounts = append(container.VolumeMounts, k8scorev1.VolumeMount{
+		Name:      summaryVolumeName,
+		MountPath: containerSummaryPath,
+	})
+	podSpec.Volumes = append(podSpec.Volumes, k8scorev1.Volume{
+		Name: summaryVolumeName,
+		VolumeSource: k8scorev1.VolumeSource{
+			EmptyDir: &k8scorev1.EmptyDirVolumeSource{},
+		},
+	})
+}
+
+// collectSummary collects the summaries of the instances of the finished job,
+// prints the merged summary and saves it to the summary file.
+// When the summaries of all instances are collected, the thresholds are evaluated against
+// the merged summary and evaluated is true.
+// Failing to collect the summary is reported but doesn't fail the run.
+func (tr *taskRunner) collectSummary(
+	ctx context.Context,
+	job *k8sbatchv1.Job,
+) (thresholdResults []ThresholdResult, evaluated bool, err error) {
+	pods, err := tr.listJobPods(ctx, job)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var summaries []*Summary
+	for idx := range pods {
+		summary, err := tr.collectK6Summary(ctx, tr.kubeClient, &pods[idx])
+		if err != nil {
+			if _, err := fmt.Fprintf(os.Stderr, "Warning: skipped summary of pod %s: %s\n", pods[idx].Name, err); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+	if len(summaries) == 0 {
+		_, err := fmt.Fprintln(os.Stderr, "Warning: no summary collected")
+		return nil, false, err
+	}
+
+	merged := MergeSummaries(summaries)
+	// partial metrics can't tell whether the thresholds passed across all instances
+	evaluated = len(summaries) == len(pods)
+	if evaluated {
+		thresholdResults = EvaluateThresholds(merged, tr.taskConfig.Thresholds)
+	}
+
+	b, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode summary: %w", err)
+	}
+	if err := os.WriteFile(tr.summaryFile, b, 0o644); err != nil {
+		return nil, false, fmt.Errorf("failed to save summary: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(
+		os.Stderr,
+		"\nSummary of %d/%d instance(s), saved to %s:\n",
+		len(summaries), len(pods), tr.summaryFile,
+	); err != nil {
+		return nil, false, err
+	}
+	if err := PrintSummary(os.Stderr, merged); err != nil {
+		return nil, false, err
+	}
+
+	if !evaluated {
+		_, err := fmt.Fprintln(os.Stderr, "\nWarning: thresholds are not evaluated across instances as some summaries are missing")
+		return nil, false, err
+	}
+	if len(thresholdResults) > 0 {
+		if _, err := fmt.Fprintln(os.Stderr, "\nThresholds across all instances:"); err != nil {
+			return nil, false, err
+		}
+		if err := PrintThresholdResults(os.Stderr, thresholdResults); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return thresholdResults, true, nil
+}
+
+// resolveThresholdsResult resolves the result of the run from the job result and the thresholds
+// evaluated across all instances. The thresholds crossed in single instances are ignored,
+// as the thresholds across all instances take precedence.
+func resolveThresholdsResult(jobErr error, thresholdResults []ThresholdResult) error {
+	var thresholdsErr *ThresholdsFailedError
+	if jobErr != nil && !errors.As(jobErr, &thresholdsErr) {
+		// pods failed for other reasons
+		return jobErr
+	}
+
+	if failed := failedThresholds(thresholdResults); len(failed) > 0 {
+		return &ThresholdsFailedError{Thresholds: failed}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/k6ctl/internal/config"
+	"github.com/Azure/k6ctl/internal/stdlib"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+func TestParseSummaryFromLogs(t *testing.T) {
+	logs := []byte("running (00m30.0s), 0/5 VUs, 100 complete and 0 interrupted iterations\n" +
+		"\n" +
+		summaryLogMarker + `{"metrics":{"http_reqs":{"count":100,"rate":3.33}}}` + "\n")
+
+	summary, err := parseSummaryFromLogs(logs)
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, summary.Metrics["http_reqs"].Values["count"])
+
+	_, err = parseSummaryFromLogs([]byte("no summary\n"))
+	assert.ErrorIs(t, err, errSummaryNotFound)
+
+	_, err = parseSummaryFromLogs([]byte(summaryLogMarker + "{\n"))
+	assert.Error(t, err)
+}
+
+func TestRunTask_Summary(t *testing.T) {
+	const namespace = "test"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	kubeClient := fake.NewSimpleClientset()
+
+	// simulate the job controller: finish the job once created
+	go func() {
+		_ = wait.PollUntilContextCancel(ctx, 10*time.Millisecond, true, func(ctx context.Context) (bool, error) {
+			jobs, err := kubeClient.BatchV1().Jobs(namespace).List(ctx, k8smetav1.ListOptions{})
+			if err != nil || len(jobs.Items) == 0 {
+				return false, nil
+			}
+			job := jobs.Items[0]
+
+			for idx := 0; idx < 3; idx++ {
+				_, err := kubeClient.CoreV1().Pods(namespace).Create(ctx, &k8scorev1.Pod{
+					ObjectMeta: k8smetav1.ObjectMeta{
+						Name:      fmt.Sprintf("pod-%d", idx),
+						Namespace: namespace,
+						Labels:    job.Spec.Selector.MatchLabels,
+						OwnerReferences: []k8smetav1.OwnerReference{
+							{Kind: "Job", Name: job.Name, UID: job.UID, Controller: stdlib.Ptr(true)},
+						},
+					},
+					Status: k8scorev1.PodStatus{Phase: k8scorev1.PodSucceeded},
+				}, k8smetav1.CreateOptions{})
+				if err != nil {
+					return false, err
+				}
+			}
+
+			job.Status.Conditions = append(job.Status.Conditions, k8sbatchv1.JobCondition{
+				Type:   k8sbatchv1.JobComplete,
+				Status: k8scorev1.ConditionTrue,
+			})
+			_, err = kubeClient.BatchV1().Jobs(namespace).UpdateStatus(ctx, &job, k8smetav1.UpdateOptions{})
+			return err == nil, err
+		})
+	}()
+
+	summaryFile := filepath.Join(t.TempDir(), "summary.json")
+	err := RunTask(
+		ctx,
+		&target.StaticTarget{Kubeconfig: "/tmp/fake-kubeconfig"},
+		config.NewRegistry().GetByName,
+		&Schema{Name: "test", K6: K6{Namespace: namespace}},
+		"./testdata/integration",
+		"test.js",
+		applyRunTaskOptionFunc(func(option *runTaskOption) error {
+			option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+				return kubeClient, nil
+			}
+			option.CollectK6Summary = func(ctx context.Context, kubeClient kubernetes.Interface, pod *k8scorev1.Pod) (*Summary, error) {
+				switch pod.Name {
+				case "pod-0":
+					return loadTestSummary(t, "instance-0.json"), nil
+				case "pod-1":
+					return loadTestSummary(t, "instance-1.json"), nil
+				default:
+					// instance failed before exporting the summary
+					return nil, errSummaryNotFound
+				}
+			}
+			option.WaitInterval = 10 * time.Millisecond
+
+			return nil
+		}),
+		WithInstances(3),
+		WithRunID("summary"),
+		WithSummary(true, summaryFile),
+		WithFollowLogs(false),
+	)
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(summaryFile)
+	assert.NoError(t, err)
+	saved := new(Summary)
+	assert.NoError(t, json.Unmarshal(b, saved))
+	assert.Equal(t, 200.0, saved.Metrics["http_reqs"].Values["count"])
+
+	job, err := kubeClient.BatchV1().Jobs(namespace).Get(ctx, "k6ctl-job-test-summary", k8smetav1.GetOptions{})
+	assert.NoError(t, err)
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"sh", "-c", summaryRunnerScript, "k6"}, container.Command)
+	assert.Equal(t, []string{"run", "test.js", "--summary-export", "/k6ctl/summary/summary.json"}, container.Args)
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	k8sbatchv1 "k8s.io/api/batch/v1"
 	k8scorev1 "k8s.io/api/core/v1"
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 
@@ -94,6 +97,7 @@ func TestRunTask_Integration(t *testing.T) {
 			return nil
 		}),
 		WithFollowLogs(false),
+		WithWaitForCompletion(false),
 		WithInstances(instances),
 	)
 	assert.NoError(t, err)
@@ -105,4 +109,208 @@ func TestRunTask_Integration(t *testing.T) {
 	job := jobsList.Items[0]
 	assert.Equal(t, stdlib.ValOrZero(job.Spec.Parallelism), int32(instances))
 	assert.Equal(t, stdlib.ValOrZero(job.Spec.Parallelism), int32(instances))
+	if assert.NotNil(t, job.Spec.BackoffLimit) {
+		assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	}
+
+	// the objects of the run are garbage-collected with the job
+	expectedOwner := []k8smetav1.OwnerReference{
+		*k8smetav1.NewControllerRef(&job, k8sbatchv1.SchemeGroupVersion.WithKind("Job")),
+	}
+	secrets, err := kubeClient.CoreV1().Secrets(namespace).List(ctx, k8smetav1.ListOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, secrets.Items, 1) {
+		assert.Equal(t, expectedOwner, secrets.Items[0].OwnerReferences)
+	}
+	configMaps, err := kubeClient.CoreV1().ConfigMaps(namespace).List(ctx, k8smetav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, configMaps.Items)
+	for _, configMap := range configMaps.Items {
+		assert.Equal(t, expectedOwner, configMap.OwnerReferences, configMap.Name)
+	}
+}
+
+func TestRunTask_RunID(t *testing.T) {
+	const namespace = "test"
+
+	taskConfig := &Schema{
+		Name: "test",
+		Files: []FileMount{
+			{
+				Source: "test.js",
+				Dest:   "test.js",
+			},
+		},
+		K6: K6{
+			Namespace: namespace,
+		},
+	}
+
+	ctx := context.Background()
+	kubeClient := fake.NewSimpleClientset()
+
+	runTask := func(options ...RunTaskOption) error {
+		return RunTask(
+			ctx,
+			&target.StaticTarget{Kubeconfig: "/tmp/fake-kubeconfig"},
+			config.NewRegistry().GetByName,
+			taskConfig,
+			"./testdata/integration",
+			"test.js",
+			append([]RunTaskOption{
+				applyRunTaskOptionFunc(func(option *runTaskOption) error {
+					option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+						return kubeClient, nil
+					}
+
+					return nil
+				}),
+				WithFollowLogs(false),
+				WithWaitForCompletion(false),
+			}, options...)...,
+		)
+	}
+
+	assert.NoError(t, runTask(WithRunID("run1")))
+	// re-run of the same task should not collide with the previous run
+	assert.NoError(t, runTask())
+	assert.Error(t, runTask(WithRunID("Invalid_ID")))
+
+	jobsList, err := kubeClient.BatchV1().Jobs(namespace).List(ctx, k8smetav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, jobsList.Items, 2)
+
+	job, err := kubeClient.BatchV1().Jobs(namespace).Get(ctx, "k6ctl-job-test-run1", k8smetav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "run1", job.Labels[labelKeyRunID])
+	assert.Equal(t, "run1", job.Spec.Selector.MatchLabels[labelKeyRunID])
+	assert.Equal(t, "run1", job.Spec.Template.Labels[labelKeyRunID])
+
+	configMaps, err := kubeClient.CoreV1().ConfigMaps(namespace).List(ctx, k8smetav1.ListOptions{
+		LabelSelector: TaskRef{Namespace: namespace, Name: "test", RunID: "run1"}.labelSelector(),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, configMaps.Items, 1)
+	assert.Equal(t, "k6ctl-scripts-config-test-run1", configMaps.Items[0].Name)
+}
+
+func TestRunTask_WaitForCompletion(t *testing.T) {
+	const namespace = "test"
+
+	runPodStatus := func(exitCode int32) k8scorev1.PodStatus {
+		phase := k8scorev1.PodSucceeded
+		if exitCode != 0 {
+			phase = k8scorev1.PodFailed
+		}
+		return k8scorev1.PodStatus{
+			Phase: phase,
+			ContainerStatuses: []k8scorev1.ContainerStatus{
+				{
+					Name: containerNameRunner,
+					State: k8scorev1.ContainerState{
+						Terminated: &k8scorev1.ContainerStateTerminated{ExitCode: exitCode},
+					},
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		name      string
+		exitCodes []int32
+
+		checkErr func(t *testing.T, err error)
+	}{
+		{
+			name:      "succeeded",
+			exitCodes: []int32{0, 0},
+			checkErr: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:      "thresholds failed",
+			exitCodes: []int32{0, 99},
+			checkErr: func(t *testing.T, err error) {
+				var thresholdsErr *ThresholdsFailedError
+				assert.ErrorAs(t, err, &thresholdsErr)
+				assert.Len(t, thresholdsErr.Pods, 1)
+				assert.Equal(t, "pod-1", thresholdsErr.Pods[0].Name)
+				assert.Equal(t, 99, thresholdsErr.ExitCode())
+			},
+		},
+		{
+			name:      "pod failed",
+			exitCodes: []int32{99, 107},
+			checkErr: func(t *testing.T, err error) {
+				var taskErr *TaskFailedError
+				assert.ErrorAs(t, err, &taskErr)
+				assert.Len(t, taskErr.Pods, 2)
+				assert.Equal(t, 1, taskErr.ExitCode())
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			kubeClient := fake.NewSimpleClientset()
+
+			// simulate the job controller: finish the job once created
+			go func() {
+				_ = wait.PollUntilContextCancel(ctx, 10*time.Millisecond, true, func(ctx context.Context) (bool, error) {
+					jobs, err := kubeClient.BatchV1().Jobs(namespace).List(ctx, k8smetav1.ListOptions{})
+					if err != nil || len(jobs.Items) == 0 {
+						return false, nil
+					}
+					job := jobs.Items[0]
+
+					for idx, exitCode := range tc.exitCodes {
+						_, err := kubeClient.CoreV1().Pods(namespace).Create(ctx, &k8scorev1.Pod{
+							ObjectMeta: k8smetav1.ObjectMeta{
+								Name:      fmt.Sprintf("pod-%d", idx),
+								Namespace: namespace,
+								Labels:    job.Spec.Selector.MatchLabels,
+								OwnerReferences: []k8smetav1.OwnerReference{
+									{Kind: "Job", Name: job.Name, UID: job.UID, Controller: stdlib.Ptr(true)},
+								},
+							},
+							Status: runPodStatus(exitCode),
+						}, k8smetav1.CreateOptions{})
+						if err != nil {
+							return false, err
+						}
+					}
+
+					job.Status.Conditions = append(job.Status.Conditions, k8sbatchv1.JobCondition{
+						Type:   k8sbatchv1.JobComplete,
+						Status: k8scorev1.ConditionTrue,
+					})
+					_, err = kubeClient.BatchV1().Jobs(namespace).UpdateStatus(ctx, &job, k8smetav1.UpdateOptions{})
+					return err == nil, err
+				})
+			}()
+
+			err := RunTask(
+				ctx,
+				&target.StaticTarget{Kubeconfig: "/tmp/fake-kubeconfig"},
+				config.NewRegistry().GetByName,
+				&Schema{Name: "test", K6: K6{Namespace: namespace}},
+				"./testdata/integration",
+				"test.js",
+				applyRunTaskOptionFunc(func(option *runTaskOption) error {
+					option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+						return kubeClient, nil
+					}
+					option.WaitInterval = 10 * time.Millisecond
+
+					return nil
+				}),
+				WithFollowLogs(false),
+			)
+			tc.checkErr(t, err)
+		})
+	}
 }
@@ -0,0 +1,123 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/Azure/k6ctl/internal/kubelib"
+)
+
+// waitForJobCompletion blocks until the job is finished and returns the latest job object.
+func (tr *taskRunner) waitForJobCompletion(
+	ctx context.Context,
+	job *k8sbatchv1.Job,
+	interval time.Duration,
+) (*k8sbatchv1.Job, error) {
+	jobsClient := tr.kubeClient.BatchV1().Jobs(job.Namespace)
+
+	var rv *k8sbatchv1.Job
+	err := wait.PollUntilContextCancel(ctx, interval, true, func(ctx context.Context) (bool, error) {
+		latest, err := jobsClient.Get(ctx, job.Name, k8smetav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		rv = latest
+		return kubelib.IsJobFinished(latest), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for job %q: %w", job.Name, err)
+	}
+
+	return rv, nil
+}
+
+// listJobPods lists the pods owned by the job.
+func (tr *taskRunner) listJobPods(ctx context.Context, job *k8sbatchv1.Job) ([]k8scorev1.Pod, error) {
+	selector, err := jobPodsSelector(job)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := tr.kubeClient.CoreV1().Pods(job.Namespace).List(ctx, k8smetav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods of job %q: %w", job.Name, err)
+	}
+
+	var rv []k8scorev1.Pod
+	for _, pod := range pods.Items {
+		if owner := k8smetav1.GetControllerOf(&pod); owner == nil || owner.UID != job.UID {
+			continue
+		}
+		rv = append(rv, pod)
+	}
+	sort.Slice(rv, func(i, j int) bool {
+		return rv[i].Name < rv[j].Name
+	})
+
+	return rv, nil
+}
+
+// checkJobResult inspects the pods of the finished job and returns an error if any of them failed.
+func (tr *taskRunner) checkJobResult(
+	ctx context.Context,
+	job *k8sbatchv1.Job,
+) error {
+	pods, err := tr.listJobPods(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	var (
+		failedPods            []FailedPod
+		thresholdsFailedCount int
+	)
+	for _, pod := range pods {
+		podStatus := buildPodStatus(pod)
+		failed := pod.Status.Phase == k8scorev1.PodFailed
+		if podStatus.ExitCode != nil && *podStatus.ExitCode != 0 {
+			failed = true
+		}
+		if !failed {
+			continue
+		}
+
+		failedPod := FailedPod{
+			Name:     pod.Name,
+			ExitCode: podStatus.ExitCode,
+			Reason:   podStatus.Reason,
+		}
+		if failedPod.Reason == "" {
+			failedPod.Reason = pod.Status.Reason
+		}
+		if podStatus.ExitCode != nil && *podStatus.ExitCode == k6ExitCodeThresholdsFailed {
+			thresholdsFailedCount++
+		}
+		failedPods = append(failedPods, failedPod)
+	}
+
+	if len(failedPods) > 0 && thresholdsFailedCount == len(failedPods) {
+		return &ThresholdsFailedError{Pods: failedPods}
+	}
+	if len(failedPods) > 0 || isJobFailed(job) {
+		return &TaskFailedError{Job: job.Name, Pods: failedPods}
+	}
+
+	return nil
+}
+
+func isJobFailed(job *k8sbatchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == k8sbatchv1.JobFailed && c.Status == k8scorev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
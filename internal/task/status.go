@@ -0,0 +1,183 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/Azure/k6ctl/internal/kubelib"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+// TaskStatus is the status of a task in the cluster.
+type TaskStatus struct {
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace"`
+	Jobs      []JobStatus `json:"jobs"`
+}
+
+// JobStatus is the status of a job created for a task.
+type JobStatus struct {
+	Name           string      `json:"name"`
+	RunID          string      `json:"runID,omitempty"`
+	Completions    int32       `json:"completions"`
+	Active         int32       `json:"active"`
+	Succeeded      int32       `json:"succeeded"`
+	Failed         int32       `json:"failed"`
+	Finished       bool        `json:"finished"`
+	StartTime      *time.Time  `json:"startTime,omitempty"`
+	CompletionTime *time.Time  `json:"completionTime,omitempty"`
+	Pods           []PodStatus `json:"pods"`
+}
+
+// PodStatus is the status of a pod created for a task job.
+type PodStatus struct {
+	Name      string     `json:"name"`
+	Phase     string     `json:"phase"`
+	Node      string     `json:"node,omitempty"`
+	StartTime *time.Time `json:"startTime,omitempty"`
+	// ExitCode is the exit code of the k6 runner container. It's nil if the container has not terminated.
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// Reason is the termination reason of the k6 runner container, or the waiting reason if it has not started.
+	Reason string `json:"reason,omitempty"`
+}
+
+// GetTaskStatus gets the status of the task's jobs and pods.
+func GetTaskStatus(
+	ctx context.Context,
+	target target.Target,
+	taskRef TaskRef,
+	options ...GetTaskStatusOption,
+) (*TaskStatus, error) {
+	opt := defaultGetTaskStatusOption()
+	for _, o := range options {
+		if err := o.apply(opt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := taskRef.validate(); err != nil {
+		return nil, err
+	}
+	namespace := taskRef.Namespace
+
+	kubeconfig, ok := target.GetKubeconfig()
+	if !ok {
+		return nil, fmt.Errorf("target does not have kubeconfig")
+	}
+	kubeClient, err := opt.KubeClientFactory(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	selector, err := resolveRunSelector(ctx, kubeClient, taskRef)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := kubeClient.BatchV1().Jobs(namespace).List(ctx, k8smetav1.ListOptions{
+		LabelSelector: selector.Labels.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	jobs.Items = slices.DeleteFunc(jobs.Items, func(job k8sbatchv1.Job) bool {
+		return !selector.matchJob(job)
+	})
+	if len(jobs.Items) == 0 {
+		return nil, fmt.Errorf("no job found for task %s", taskRef)
+	}
+	pods, err := kubeClient.CoreV1().Pods(namespace).List(ctx, k8smetav1.ListOptions{
+		LabelSelector: selector.podLabels().String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	podsByJob := map[types.UID][]k8scorev1.Pod{}
+	for _, pod := range pods.Items {
+		if owner := k8smetav1.GetControllerOf(&pod); owner != nil {
+			podsByJob[owner.UID] = append(podsByJob[owner.UID], pod)
+		}
+	}
+
+	sort.Slice(jobs.Items, func(i, j int) bool {
+		return jobs.Items[i].CreationTimestamp.Before(&jobs.Items[j].CreationTimestamp)
+	})
+
+	rv := &TaskStatus{
+		Name:      taskRef.Name,
+		Namespace: namespace,
+	}
+	for _, job := range jobs.Items {
+		rv.Jobs = append(rv.Jobs, buildJobStatus(job, podsByJob[job.UID]))
+	}
+
+	return rv, nil
+}
+
+func metaTimeOrNil(t *k8smetav1.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	rv := t.Time
+	return &rv
+}
+
+func buildJobStatus(job k8sbatchv1.Job, pods []k8scorev1.Pod) JobStatus {
+	rv := JobStatus{
+		Name:           job.Name,
+		RunID:          jobRunID(job),
+		Active:         job.Status.Active,
+		Succeeded:      job.Status.Succeeded,
+		Failed:         job.Status.Failed,
+		Finished:       kubelib.IsJobFinished(&job),
+		StartTime:      metaTimeOrNil(job.Status.StartTime),
+		CompletionTime: metaTimeOrNil(job.Status.CompletionTime),
+		Pods:           []PodStatus{},
+	}
+	if job.Spec.Completions != nil {
+		rv.Completions = *job.Spec.Completions
+	}
+
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+	for _, pod := range pods {
+		rv.Pods = append(rv.Pods, buildPodStatus(pod))
+	}
+
+	return rv
+}
+
+func buildPodStatus(pod k8scorev1.Pod) PodStatus {
+	rv := PodStatus{
+		Name:      pod.Name,
+		Phase:     string(pod.Status.Phase),
+		Node:      pod.Spec.NodeName,
+		StartTime: metaTimeOrNil(pod.Status.StartTime),
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != containerNameRunner {
+			continue
+		}
+
+		switch {
+		case cs.State.Terminated != nil:
+			rv.ExitCode = &cs.State.Terminated.ExitCode
+			rv.Reason = cs.State.Terminated.Reason
+		case cs.State.Waiting != nil:
+			rv.Reason = cs.State.Waiting.Reason
+		}
+	}
+
+	return rv
+}
@@ -0,0 +1,27 @@
+package task
+
+import "github.com/Azure/k6ctl/internal/kubelib"
+
+type getTaskStatusOption struct {
+	// KubeClientFactory provides the kubernetes client to use for the task.
+	// If not provided, createKubeClientFromKubeConfig is used.
+	// Unit test can provide a mock implementation.
+	KubeClientFactory kubelib.KubeClientFactory
+}
+
+func defaultGetTaskStatusOption() *getTaskStatusOption {
+	return &getTaskStatusOption{
+		KubeClientFactory: kubelib.CreateKubeClientFromKubeConfig,
+	}
+}
+
+// GetTaskStatusOption configures the behavior of GetTaskStatus.
+type GetTaskStatusOption interface {
+	apply(option *getTaskStatusOption) error
+}
+
+type applyGetTaskStatusOptionFunc func(option *getTaskStatusOption) error
+
+func (f applyGetTaskStatusOptionFunc) apply(option *getTaskStatusOption) error {
+	return f(option)
+}
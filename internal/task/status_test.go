@@ -0,0 +1,118 @@
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/k6ctl/internal/stdlib"
+	"github.com/Azure/k6ctl/internal/target"
+)
+
+func TestGetTaskStatus(t *testing.T) {
+	const namespace = "test"
+
+	labels := map[string]string{labelKeyTaskName: "test"}
+	job := &k8sbatchv1.Job{
+		ObjectMeta: k8smetav1.ObjectMeta{
+			Name:      "k6ctl-job-test",
+			Namespace: namespace,
+			Labels:    labels,
+			UID:       "job-uid",
+		},
+		Spec: k8sbatchv1.JobSpec{
+			Completions: stdlib.Ptr[int32](2),
+		},
+		Status: k8sbatchv1.JobStatus{
+			Active:    1,
+			Succeeded: 1,
+		},
+	}
+	ownerRefs := []k8smetav1.OwnerReference{
+		{Kind: "Job", Name: job.Name, UID: job.UID, Controller: stdlib.Ptr(true)},
+	}
+
+	kubeClient := fake.NewSimpleClientset(
+		job,
+		&k8scorev1.Pod{
+			ObjectMeta: k8smetav1.ObjectMeta{
+				Name:            "k6ctl-job-test-b",
+				Namespace:       namespace,
+				Labels:          labels,
+				OwnerReferences: ownerRefs,
+			},
+			Spec: k8scorev1.PodSpec{NodeName: "node-2"},
+			Status: k8scorev1.PodStatus{
+				Phase: k8scorev1.PodRunning,
+			},
+		},
+		&k8scorev1.Pod{
+			ObjectMeta: k8smetav1.ObjectMeta{
+				Name:            "k6ctl-job-test-a",
+				Namespace:       namespace,
+				Labels:          labels,
+				OwnerReferences: ownerRefs,
+			},
+			Spec: k8scorev1.PodSpec{NodeName: "node-1"},
+			Status: k8scorev1.PodStatus{
+				Phase: k8scorev1.PodSucceeded,
+				ContainerStatuses: []k8scorev1.ContainerStatus{
+					{
+						Name: containerNameRunner,
+						State: k8scorev1.ContainerState{
+							Terminated: &k8scorev1.ContainerStateTerminated{
+								ExitCode: 0,
+								Reason:   "Completed",
+							},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	fakeTarget := &target.StaticTarget{
+		Kubeconfig: "/tmp/fake-kubeconfig",
+	}
+	withFakeClient := applyGetTaskStatusOptionFunc(func(option *getTaskStatusOption) error {
+		option.KubeClientFactory = func(kubeconfig string) (kubernetes.Interface, error) {
+			return kubeClient, nil
+		}
+		return nil
+	})
+
+	ctx := context.Background()
+
+	status, err := GetTaskStatus(ctx, fakeTarget, TaskRef{Namespace: namespace, Name: "test"}, withFakeClient)
+	assert.NoError(t, err)
+	assert.Len(t, status.Jobs, 1)
+
+	jobStatus := status.Jobs[0]
+	assert.Equal(t, int32(2), jobStatus.Completions)
+	assert.Equal(t, int32(1), jobStatus.Active)
+	assert.Equal(t, int32(1), jobStatus.Succeeded)
+	assert.False(t, jobStatus.Finished)
+	assert.Equal(t, []PodStatus{
+		{
+			Name:     "k6ctl-job-test-a",
+			Phase:    "Succeeded",
+			Node:     "node-1",
+			ExitCode: stdlib.Ptr[int32](0),
+			Reason:   "Completed",
+		},
+		{
+			Name:  "k6ctl-job-test-b",
+			Phase: "Running",
+			Node:  "node-2",
+		},
+	}, jobStatus.Pods)
+
+	_, err = GetTaskStatus(ctx, fakeTarget, TaskRef{Namespace: namespace, Name: "missing"}, withFakeClient)
+	assert.Error(t, err)
+}
@@ -0,0 +1,386 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Summary is the k6 end-of-test summary in the --summary-export format.
+// ref: https://grafana.com/docs/k6/latest/results-output/end-of-test/
+type Summary struct {
+	RootGroup SummaryGroup             `json:"root_group"`
+	Options   map[string]any           `json:"options,omitempty"`
+	State     SummaryState             `json:"state"`
+	Metrics   map[string]SummaryMetric `json:"metrics"`
+}
+
+// SummaryState is the state of the test run in the summary.
+type SummaryState struct {
+	IsStdOutTTY       bool    `json:"isStdOutTTY"`
+	IsStdErrTTY       bool    `json:"isStdErrTTY"`
+	TestRunDurationMs float64 `json:"testRunDurationMs"`
+}
+
+// SummaryGroup is a k6 group with its checks in the summary.
+type SummaryGroup struct {
+	Name   string                  `json:"name"`
+	Path   string                  `json:"path"`
+	ID     string                  `json:"id"`
+	Groups map[string]SummaryGroup `json:"groups"`
+	Checks map[string]SummaryCheck `json:"checks"`
+}
+
+// SummaryCheck is the result of a k6 check in the summary.
+type SummaryCheck struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	ID     string `json:"id"`
+	Passes int64  `json:"passes"`
+	Fails  int64  `json:"fails"`
+}
+
+// SummaryMetric is a metric in the summary. The exported format flattens the values
+// and the threshold results into the same object.
+type SummaryMetric struct {
+	Values map[string]float64
+	// Thresholds maps the threshold expressions to whether they failed.
+	Thresholds map[string]bool
+	// Unmerged lists the values which couldn't be merged exactly from the instances. They are
+	// kept for display, but the thresholds on them can't be evaluated. It's not in the exported format.
+	Unmerged []string
+	// UpperBounds lists the values merged as an upper bound of the real value, only the thresholds
+	// checking that they are below a limit (< and <=) can be evaluated. It's not in the exported format.
+	UpperBounds []string
+}
+
+func (m *SummaryMetric) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	m.Values = map[string]float64{}
+	m.Thresholds = nil
+	for k, v := range raw {
+		if k == "thresholds" {
+			if err := json.Unmarshal(v, &m.Thresholds); err != nil {
+				return fmt.Errorf("thresholds: %w", err)
+			}
+			continue
+		}
+		var f float64
+		if err := json.Unmarshal(v, &f); err != nil {
+			return fmt.Errorf("%s: %w", k, err)
+		}
+		m.Values[k] = f
+	}
+
+	return nil
+}
+
+func (m SummaryMetric) MarshalJSON() ([]byte, error) {
+	raw := make(map[string]any, len(m.Values)+1)
+	for k, v := range m.Values {
+		raw[k] = v
+	}
+	if len(m.Thresholds) > 0 {
+		raw["thresholds"] = m.Thresholds
+	}
+	return json.Marshal(raw)
+}
+
+const (
+	summaryMetricTypeCounter = "counter"
+	summaryMetricTypeGauge   = "gauge"
+	summaryMetricTypeRate    = "rate"
+	summaryMetricTypeTrend   = "trend"
+)
+
+// Type infers the k6 metric type from the exported values.
+func (m SummaryMetric) Type() string {
+	has := func(k string) bool {
+		_, ok := m.Values[k]
+		return ok
+	}
+	switch {
+	case has("count") && has("rate"):
+		// trends export count without rate when it's listed in summaryTrendStats
+		return summaryMetricTypeCounter
+	case has("passes") || has("fails"):
+		return summaryMetricTypeRate
+	case has("value"):
+		return summaryMetricTypeGauge
+	default:
+		return summaryMetricTypeTrend
+	}
+}
+
+// MergeSummaries merges the summaries of the instances of a run into one.
+//
+// Instances run concurrently, so counters and gauge values are added up, gauge min and max are the
+// extremes across the instances, and rates are recomputed from the total passes and fails.
+// Trend percentiles can't be recomputed from the exported values: min and max are exact, and the
+// other stats (med, p(N)...) are the maximum across the instances, which is an upper bound of the
+// real value listed in UpperBounds. avg is the mean of the instances weighted by their sample counts,
+// read from the count stat of the trend, or from the counter of the built-in trends (http_reqs for
+// http_req_*). Without the counts, avg is the unweighted mean and listed in Unmerged, see trendSampleCount.
+// A threshold is reported as failed if it failed in any instance. Use EvaluateThresholds
+// to evaluate the thresholds against the merged metrics instead.
+func MergeSummaries(summaries []*Summary) *Summary {
+	rv := &Summary{
+		Metrics: map[string]SummaryMetric{},
+	}
+	if len(summaries) == 0 {
+		return rv
+	}
+
+	rv.Options = summaries[0].Options
+	rv.State = summaries[0].State
+	rv.RootGroup = summaries[0].RootGroup
+	for _, s := range summaries[1:] {
+		rv.State.TestRunDurationMs = math.Max(rv.State.TestRunDurationMs, s.State.TestRunDurationMs)
+		rv.RootGroup = mergeSummaryGroups(rv.RootGroup, s.RootGroup)
+	}
+
+	metricSummaries := map[string][]SummaryMetric{}
+	// metricCounts are the sample counts of the trends in each instance, NaN if unknown.
+	metricCounts := map[string][]float64{}
+	for _, s := range summaries {
+		for name, m := range s.Metrics {
+			metricSummaries[name] = append(metricSummaries[name], m)
+			metricCounts[name] = append(metricCounts[name], trendSampleCount(s, name))
+		}
+	}
+	for name, ms := range metricSummaries {
+		rv.Metrics[name] = mergeSummaryMetrics(ms, metricCounts[name])
+	}
+
+	return rv
+}
+
+func mergeSummaryGroups(a, b SummaryGroup) SummaryGroup {
+	rv := SummaryGroup{
+		Name:   a.Name,
+		Path:   a.Path,
+		ID:     a.ID,
+		Groups: map[string]SummaryGroup{},
+		Checks: map[string]SummaryCheck{},
+	}
+	for k, g := range a.Groups {
+		rv.Groups[k] = g
+	}
+	for k, g := range b.Groups {
+		if existing, ok := rv.Groups[k]; ok {
+			g = mergeSummaryGroups(existing, g)
+		}
+		rv.Groups[k] = g
+	}
+	for k, c := range a.Checks {
+		rv.Checks[k] = c
+	}
+	for k, c := range b.Checks {
+		if existing, ok := rv.Checks[k]; ok {
+			c.Passes += existing.Passes
+			c.Fails += existing.Fails
+		}
+		rv.Checks[k] = c
+	}
+	return rv
+}
+
+// trendCounters maps the built-in trends to the counters incremented once per sample of the trend.
+var trendCounters = map[string]string{
+	"http_req_blocked":         "http_reqs",
+	"http_req_connecting":      "http_reqs",
+	"http_req_duration":        "http_reqs",
+	"http_req_receiving":       "http_reqs",
+	"http_req_sending":         "http_reqs",
+	"http_req_tls_handshaking": "http_reqs",
+	"http_req_waiting":         "http_reqs",
+	"iteration_duration":       "iterations",
+}
+
+// trendSampleCount returns the number of samples of the trend in the summary, NaN if unknown.
+func trendSampleCount(s *Summary, name string) float64 {
+	if v, ok := s.Metrics[name].Values["count"]; ok {
+		return v
+	}
+	if counter, ok := trendCounters[name]; ok {
+		if v, ok := s.Metrics[counter].Values["count"]; ok {
+			return v
+		}
+	}
+	return math.NaN()
+}
+
+// weightedMean returns the mean of the values weighted by the counts. ok is false if any count is unknown.
+func weightedMean(values []float64, counts []float64) (float64, bool) {
+	var sum, total float64
+	for idx, v := range values {
+		if math.IsNaN(counts[idx]) {
+			return 0, false
+		}
+		sum += v * counts[idx]
+		total += counts[idx]
+	}
+	if total == 0 {
+		return 0, true
+	}
+	return sum / total, true
+}
+
+// mergeSummaryMetrics merges the metric of the instances. counts are the sample counts of the trend
+// in each instance, see trendSampleCount.
+func mergeSummaryMetrics(ms []SummaryMetric, counts []float64) SummaryMetric {
+	rv := SummaryMetric{Values: map[string]float64{}}
+	metricType := ms[0].Type()
+
+	for _, m := range ms {
+		for expr, failed := range m.Thresholds {
+			if rv.Thresholds == nil {
+				rv.Thresholds = map[string]bool{}
+			}
+			rv.Thresholds[expr] = rv.Thresholds[expr] || failed
+		}
+	}
+
+	keyValues := map[string][]float64{}
+	// keyCounts are the counts of the instances in keyValues, as the key may be missing from some instances.
+	keyCounts := map[string][]float64{}
+	for idx, m := range ms {
+		for k, v := range m.Values {
+			keyValues[k] = append(keyValues[k], v)
+			keyCounts[k] = append(keyCounts[k], counts[idx])
+		}
+	}
+
+	for k, values := range keyValues {
+		switch {
+		case metricType == summaryMetricTypeGauge && k == "min":
+			rv.Values[k] = minFloats(values)
+		case metricType == summaryMetricTypeGauge && k == "max":
+			rv.Values[k] = maxFloats(values)
+		case metricType == summaryMetricTypeCounter, metricType == summaryMetricTypeGauge:
+			rv.Values[k] = sumFloats(values)
+		case metricType == summaryMetricTypeRate:
+			// value is recomputed below
+			rv.Values[k] = sumFloats(values)
+		case k == "min":
+			rv.Values[k] = minFloats(values)
+		case k == "avg" && len(values) == 1:
+			rv.Values[k] = values[0]
+		case k == "avg":
+			if avg, ok := weightedMean(values, keyCounts[k]); ok {
+				rv.Values[k] = avg
+				continue
+			}
+			rv.Values[k] = sumFloats(values) / float64(len(values))
+			rv.Unmerged = append(rv.Unmerged, k)
+		case k == "count":
+			rv.Values[k] = sumFloats(values)
+		case k == "max" || len(values) == 1:
+			rv.Values[k] = maxFloats(values)
+		default:
+			// med and percentiles
+			rv.Values[k] = maxFloats(values)
+			rv.UpperBounds = append(rv.UpperBounds, k)
+		}
+	}
+
+	if metricType == summaryMetricTypeRate {
+		passes, fails := rv.Values["passes"], rv.Values["fails"]
+		if passes+fails > 0 {
+			rv.Values["value"] = passes / (passes + fails)
+		} else {
+			rv.Values["value"] = 0
+		}
+	}
+
+	return rv
+}
+
+func sumFloats(values []float64) float64 {
+	var rv float64
+	for _, v := range values {
+		rv += v
+	}
+	return rv
+}
+
+func minFloats(values []float64) float64 {
+	rv := values[0]
+	for _, v := range values[1:] {
+		rv = math.Min(rv, v)
+	}
+	return rv
+}
+
+func maxFloats(values []float64) float64 {
+	rv := values[0]
+	for _, v := range values[1:] {
+		rv = math.Max(rv, v)
+	}
+	return rv
+}
+
+// summaryValueKeyOrder is the display order of the well-known metric value keys.
+var summaryValueKeyOrder = []string{"count", "rate", "value", "passes", "fails", "avg", "min", "med", "max"}
+
+func sortedSummaryValueKeys(values map[string]float64) []string {
+	order := map[string]int{}
+	for idx, k := range summaryValueKeyOrder {
+		order[k] = idx
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		oi, iok := order[keys[i]]
+		oj, jok := order[keys[j]]
+		switch {
+		case iok && jok:
+			return oi < oj
+		case iok != jok:
+			return iok
+		default:
+			return keys[i] < keys[j]
+		}
+	})
+	return keys
+}
+
+func formatSummaryValue(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// PrintSummary prints the metrics of the summary in a human-readable format.
+func PrintSummary(w io.Writer, summary *Summary) error {
+	names := make([]string, 0, len(summary.Metrics))
+	for name := range summary.Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		m := summary.Metrics[name]
+		values := make([]string, 0, len(m.Values))
+		for _, k := range sortedSummaryValueKeys(m.Values) {
+			values = append(values, fmt.Sprintf("%s=%s", k, formatSummaryValue(m.Values[k])))
+		}
+		if _, err := fmt.Fprintf(tw, "%s\t%s\n", name, strings.Join(values, " ")); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,145 @@
+package task
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Azure/k6ctl/internal/stdlib"
+)
+
+func loadTestSummary(t *testing.T, name string) *Summary {
+	t.Helper()
+
+	b, err := os.ReadFile("./testdata/summary/" + name)
+	assert.NoError(t, err)
+	rv := new(Summary)
+	assert.NoError(t, json.Unmarshal(b, rv))
+	return rv
+}
+
+func TestSummaryMetric_JSON(t *testing.T) {
+	s := loadTestSummary(t, "instance-0.json")
+
+	m := s.Metrics["http_req_duration"]
+	assert.Equal(t, summaryMetricTypeTrend, m.Type())
+	assert.Equal(t, 300.0, m.Values["p(95)"])
+	assert.Equal(t, map[string]bool{"p(95)<500": false}, m.Thresholds)
+	assert.Equal(t, summaryMetricTypeCounter, s.Metrics["http_reqs"].Type())
+	assert.Equal(t, summaryMetricTypeGauge, s.Metrics["vus_max"].Type())
+	assert.Equal(t, summaryMetricTypeRate, s.Metrics["checks"].Type())
+
+	b, err := json.Marshal(m)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"avg":100,"min":10,"med":90,"max":400,"p(90)":200,"p(95)":300,"thresholds":{"p(95)<500":false}}`, string(b))
+}
+
+func TestMergeSummaries(t *testing.T) {
+	merged := MergeSummaries([]*Summary{
+		loadTestSummary(t, "instance-0.json"),
+		loadTestSummary(t, "instance-1.json"),
+	})
+
+	assert.Equal(t, 30020.0, merged.State.TestRunDurationMs)
+	assert.Equal(t, SummaryCheck{
+		Name:   "status is 200",
+		Path:   "::status is 200",
+		ID:     "6210a8cd14cd70477eba5c5e4cb3fb5f",
+		Passes: 190,
+		Fails:  10,
+	}, merged.RootGroup.Checks["status is 200"])
+
+	assert.Equal(t, map[string]float64{"count": 200, "rate": 6.66}, merged.Metrics["http_reqs"].Values)
+	// gauge values are added up, while min and max are the extremes of the instances
+	assert.Equal(t, map[string]float64{"value": 10, "min": 5, "max": 5}, merged.Metrics["vus_max"].Values)
+	assert.Equal(t, map[string]float64{"passes": 190, "fails": 10, "value": 0.95}, merged.Metrics["checks"].Values)
+	assert.Equal(t, map[string]float64{
+		"avg":   150,
+		"min":   10,
+		"med":   150,
+		"max":   600,
+		"p(90)": 450,
+		"p(95)": 550,
+	}, merged.Metrics["http_req_duration"].Values)
+	assert.Equal(t, map[string]bool{"p(95)<500": true}, merged.Metrics["http_req_duration"].Thresholds)
+	assert.ElementsMatch(t, []string{"med", "p(90)", "p(95)"}, merged.Metrics["http_req_duration"].UpperBounds)
+}
+
+func TestMergeSummaries_Gauge(t *testing.T) {
+	merged := MergeSummaries([]*Summary{
+		{Metrics: map[string]SummaryMetric{"response_size": {Values: map[string]float64{"value": 300, "min": 100, "max": 300}}}},
+		{Metrics: map[string]SummaryMetric{"response_size": {Values: map[string]float64{"value": 200, "min": 50, "max": 200}}}},
+		{Metrics: map[string]SummaryMetric{"response_size": {Values: map[string]float64{"value": 400, "min": 80, "max": 400}}}},
+	})
+
+	assert.Equal(t, map[string]float64{"value": 900, "min": 50, "max": 400}, merged.Metrics["response_size"].Values)
+}
+
+func TestMergeSummaries_TrendAvg(t *testing.T) {
+	instance := func(requests float64, metrics map[string]SummaryMetric) *Summary {
+		metrics["http_reqs"] = SummaryMetric{Values: map[string]float64{"count": requests, "rate": 1}}
+		return &Summary{Metrics: metrics}
+	}
+	merged := MergeSummaries([]*Summary{
+		instance(300, map[string]SummaryMetric{
+			"http_req_duration": {Values: map[string]float64{"avg": 100, "max": 400}},
+			"ws_connecting":     {Values: map[string]float64{"avg": 10, "count": 1}},
+			"custom_waiting":    {Values: map[string]float64{"avg": 10}},
+		}),
+		instance(100, map[string]SummaryMetric{
+			"http_req_duration": {Values: map[string]float64{"avg": 200, "max": 600}},
+			"ws_connecting":     {Values: map[string]float64{"avg": 30, "count": 3}},
+			"custom_waiting":    {Values: map[string]float64{"avg": 30}},
+		}),
+	})
+
+	// weighted by http_reqs
+	assert.Equal(t, map[string]float64{"avg": 125, "max": 600}, merged.Metrics["http_req_duration"].Values)
+	assert.Empty(t, merged.Metrics["http_req_duration"].Unmerged)
+	// weighted by the exported count
+	assert.Equal(t, summaryMetricTypeTrend, merged.Metrics["ws_connecting"].Type())
+	assert.Equal(t, map[string]float64{"avg": 25, "count": 4}, merged.Metrics["ws_connecting"].Values)
+	assert.Empty(t, merged.Metrics["ws_connecting"].Unmerged)
+	// unknown counts
+	assert.Equal(t, map[string]float64{"avg": 20}, merged.Metrics["custom_waiting"].Values)
+	assert.Equal(t, []string{"avg"}, merged.Metrics["custom_waiting"].Unmerged)
+
+	results := EvaluateThresholds(merged, map[string][]string{
+		"http_req_duration": {"avg<150"},
+		"custom_waiting":    {"avg<100"},
+	})
+	assert.Equal(t, []ThresholdResult{
+		{
+			Metric:     "custom_waiting",
+			Expression: "avg<100",
+			Error: "avg can't be merged from the instances without the sample counts, " +
+				"add count to the summaryTrendStats option",
+		},
+		{Metric: "http_req_duration", Expression: "avg<150", Value: stdlib.Ptr(125.0), Passed: true},
+	}, results)
+}
+
+func TestMergeSummaries_Empty(t *testing.T) {
+	merged := MergeSummaries(nil)
+	assert.Empty(t, merged.Metrics)
+}
+
+func TestPrintSummary(t *testing.T) {
+	var out bytes.Buffer
+	err := PrintSummary(&out, &Summary{
+		Metrics: map[string]SummaryMetric{
+			"http_reqs":         {Values: map[string]float64{"rate": 6.666, "count": 200}},
+			"http_req_duration": {Values: map[string]float64{"p(95)": 550, "avg": 150.5, "min": 10}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"http_req_duration  avg=150.5 min=10 p(95)=550\n"+
+			"http_reqs          count=200 rate=6.67\n",
+		out.String(),
+	)
+}
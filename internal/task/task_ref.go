@@ -0,0 +1,135 @@
+package task
+
+import (
+	"context"
+	"fmt"
+
+	k8sbatchv1 "k8s.io/api/batch/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+)
+
+const runIDLength = 8
+
+// NewRunID generates a random run ID for a task run.
+func NewRunID() string {
+	return utilrand.String(runIDLength)
+}
+
+// TaskRef references the objects of a task created in the cluster.
+type TaskRef struct {
+	// Namespace - namespace of the task objects.
+	Namespace string
+	// Name - name of the task.
+	Name string
+	// RunID - optional run ID to select a single run of the task.
+	// Empty value selects all runs of the task.
+	RunID string
+}
+
+func (r TaskRef) validate() error {
+	if r.Namespace == "" {
+		return fmt.Errorf("namespace is required")
+	}
+	if r.Name == "" {
+		return fmt.Errorf("task name is required")
+	}
+	return nil
+}
+
+// labels returns the labels of the task objects selected by the reference.
+func (r TaskRef) labels() labels.Set {
+	rv := labels.Set{
+		labelKeyTaskName: r.Name,
+	}
+	if r.RunID != "" {
+		rv[labelKeyRunID] = r.RunID
+	}
+	return rv
+}
+
+// labelSelector returns the label selector string of the task objects selected by the reference.
+func (r TaskRef) labelSelector() string {
+	return r.labels().String()
+}
+
+func (r TaskRef) String() string {
+	if r.RunID == "" {
+		return fmt.Sprintf("%s/%s", r.Namespace, r.Name)
+	}
+	return fmt.Sprintf("%s/%s (run %s)", r.Namespace, r.Name, r.RunID)
+}
+
+// runSelector selects the objects of the runs referenced by a TaskRef.
+//
+// Runs started by k6ctl are labelled with their run ID. Runs scheduled by a CronJob share the labels
+// of its job template, so such a run is selected by the job name generated by the CronJob controller,
+// which is reported as its run ID.
+type runSelector struct {
+	// Labels selects the objects of the runs.
+	Labels labels.Set
+	// JobName selects the job of a scheduled run. Only its job and pods are selected then.
+	JobName string
+}
+
+// resolveRunSelector resolves the selector of the runs referenced by the TaskRef.
+func resolveRunSelector(ctx context.Context, kubeClient kubernetes.Interface, r TaskRef) (runSelector, error) {
+	rv := runSelector{Labels: r.labels()}
+	if r.RunID == "" {
+		return rv, nil
+	}
+
+	jobsClient := kubeClient.BatchV1().Jobs(r.Namespace)
+	jobs, err := jobsClient.List(ctx, k8smetav1.ListOptions{LabelSelector: r.labelSelector()})
+	if err != nil {
+		return runSelector{}, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	if len(jobs.Items) > 0 {
+		return rv, nil
+	}
+
+	job, err := jobsClient.Get(ctx, r.RunID, k8smetav1.GetOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		return rv, nil
+	case err != nil:
+		return runSelector{}, fmt.Errorf("failed to get job %q: %w", r.RunID, err)
+	}
+	if job.Labels[labelKeyTaskName] != r.Name || job.Labels[labelKeyRunID] != "" {
+		return rv, nil
+	}
+
+	return runSelector{
+		Labels:  TaskRef{Namespace: r.Namespace, Name: r.Name}.labels(),
+		JobName: job.Name,
+	}, nil
+}
+
+// podLabels returns the labels of the pods of the selected runs.
+func (s runSelector) podLabels() labels.Set {
+	if s.JobName == "" {
+		return s.Labels
+	}
+
+	rv := labels.Set{labelKeyJobName: s.JobName}
+	for k, v := range s.Labels {
+		rv[k] = v
+	}
+	return rv
+}
+
+// matchJob reports whether the job, selected by the labels already, belongs to the selected runs.
+func (s runSelector) matchJob(job k8sbatchv1.Job) bool {
+	return s.JobName == "" || job.Name == s.JobName
+}
+
+// jobRunID returns the run ID of the job: the run ID label, or the job name for the runs scheduled by a CronJob.
+func jobRunID(job k8sbatchv1.Job) string {
+	if runID := job.Labels[labelKeyRunID]; runID != "" {
+		return runID
+	}
+	return job.Name
+}
@@ -0,0 +1,232 @@
+package task
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ThresholdResult is the result of a threshold evaluated against the merged summary.
+type ThresholdResult struct {
+	Metric     string
+	Expression string
+	// Value is the metric value the threshold is evaluated against. It's nil if the value is not available.
+	Value *float64
+	// UpperBound reports whether Value is an upper bound of the real value, see SummaryMetric.UpperBounds.
+	UpperBound bool
+	// Passed reports whether the threshold passed.
+	Passed bool
+	// Error describes why the threshold couldn't be evaluated. Such threshold is considered failed.
+	Error string
+}
+
+func (r ThresholdResult) String() string {
+	return fmt.Sprintf("%s: %s", r.Metric, r.Expression)
+}
+
+// thresholdExpressionPattern matches the k6 threshold expressions: <aggregation> <operator> <value>.
+// ref: https://grafana.com/docs/k6/latest/using-k6/thresholds/#threshold-syntax
+var thresholdExpressionPattern = regexp.MustCompile(
+	`^\s*([a-z]+(?:\(\s*[0-9.]+\s*\))?)\s*(<=|>=|===|==|!=|<|>)\s*(\S+)\s*$`,
+)
+
+type thresholdExpression struct {
+	Aggregation string
+	Operator    string
+	Value       float64
+}
+
+func parseThresholdExpression(expr string) (thresholdExpression, error) {
+	m := thresholdExpressionPattern.FindStringSubmatch(expr)
+	if m == nil {
+		return thresholdExpression{}, fmt.Errorf("invalid threshold expression %q", expr)
+	}
+	value, err := strconv.ParseFloat(m[3], 64)
+	if err != nil {
+		return thresholdExpression{}, fmt.Errorf("invalid threshold value %q in %q", m[3], expr)
+	}
+
+	return thresholdExpression{
+		Aggregation: strings.ReplaceAll(m[1], " ", ""),
+		Operator:    m[2],
+		Value:       value,
+	}, nil
+}
+
+func (e thresholdExpression) evaluate(v float64) bool {
+	switch e.Operator {
+	case "<":
+		return v < e.Value
+	case "<=":
+		return v <= e.Value
+	case ">":
+		return v > e.Value
+	case ">=":
+		return v >= e.Value
+	case "==", "===":
+		return v == e.Value
+	case "!=":
+		return v != e.Value
+	default:
+		return false
+	}
+}
+
+// summaryMetricValue looks up the value of the threshold aggregation in the metric.
+func summaryMetricValue(m SummaryMetric, aggregation string) (float64, bool) {
+	key := aggregation
+	if aggregation == "rate" && m.Type() == summaryMetricTypeRate {
+		// the rate of rate metrics is exported as value
+		key = "value"
+	}
+	v, ok := m.Values[key]
+	return v, ok
+}
+
+// ValidateThresholds validates the threshold expressions.
+func ValidateThresholds(thresholds map[string][]string) error {
+	for metric, exprs := range thresholds {
+		for _, expr := range exprs {
+			if _, err := parseThresholdExpression(expr); err != nil {
+				return fmt.Errorf("thresholds.%s: %w", metric, err)
+			}
+		}
+	}
+	return nil
+}
+
+// EvaluateThresholds evaluates the thresholds against the merged summary. The thresholds declared
+// in the script are read from the summary, and the extra thresholds are added to them.
+// The results are recorded in the summary metrics, and returned sorted by metric and expression.
+func EvaluateThresholds(summary *Summary, extra map[string][]string) []ThresholdResult {
+	thresholds := map[string]map[string]struct{}{}
+	add := func(metric string, expr string) {
+		if _, ok := thresholds[metric]; !ok {
+			thresholds[metric] = map[string]struct{}{}
+		}
+		thresholds[metric][expr] = struct{}{}
+	}
+	for metric, m := range summary.Metrics {
+		for expr := range m.Thresholds {
+			add(metric, expr)
+		}
+	}
+	for metric, exprs := range extra {
+		for _, expr := range exprs {
+			add(metric, expr)
+		}
+	}
+
+	var rv []ThresholdResult
+	for metric, exprs := range thresholds {
+		for expr := range exprs {
+			rv = append(rv, evaluateThreshold(summary, metric, expr))
+		}
+	}
+	sort.Slice(rv, func(i, j int) bool {
+		if rv[i].Metric != rv[j].Metric {
+			return rv[i].Metric < rv[j].Metric
+		}
+		return rv[i].Expression < rv[j].Expression
+	})
+
+	for _, r := range rv {
+		m, ok := summary.Metrics[r.Metric]
+		if !ok {
+			continue
+		}
+		if m.Thresholds == nil {
+			m.Thresholds = map[string]bool{}
+		}
+		// the exported format records whether the threshold failed
+		m.Thresholds[r.Expression] = !r.Passed
+		summary.Metrics[r.Metric] = m
+	}
+
+	return rv
+}
+
+func evaluateThreshold(summary *Summary, metric string, expr string) ThresholdResult {
+	rv := ThresholdResult{
+		Metric:     metric,
+		Expression: expr,
+	}
+
+	parsed, err := parseThresholdExpression(expr)
+	if err != nil {
+		rv.Error = err.Error()
+		return rv
+	}
+	m, ok := summary.Metrics[metric]
+	if !ok {
+		rv.Error = "metric not found in the summary"
+		return rv
+	}
+	v, ok := summaryMetricValue(m, parsed.Aggregation)
+	if !ok {
+		rv.Error = fmt.Sprintf("%s is not in the summary", parsed.Aggregation)
+		if m.Type() == summaryMetricTypeTrend {
+			rv.Error += ", add it to the summaryTrendStats option"
+		}
+		return rv
+	}
+
+	if slices.Contains(m.Unmerged, parsed.Aggregation) {
+		rv.Error = fmt.Sprintf(
+			"%s can't be merged from the instances without the sample counts, add count to the summaryTrendStats option",
+			parsed.Aggregation,
+		)
+		return rv
+	}
+
+	if slices.Contains(m.UpperBounds, parsed.Aggregation) {
+		if parsed.Operator != "<" && parsed.Operator != "<=" {
+			rv.Error = fmt.Sprintf(
+				"%s merged from the instances is an upper bound, only < and <= thresholds can be evaluated",
+				parsed.Aggregation,
+			)
+			return rv
+		}
+		rv.UpperBound = true
+	}
+
+	rv.Value = &v
+	rv.Passed = parsed.evaluate(v)
+	return rv
+}
+
+// PrintThresholdResults prints the results of the thresholds.
+func PrintThresholdResults(w io.Writer, results []ThresholdResult) error {
+	for _, r := range results {
+		status := "PASS"
+		if !r.Passed {
+			status = "FAIL"
+		}
+		detail := r.Error
+		switch {
+		case r.Value != nil && r.UpperBound:
+			detail = fmt.Sprintf("actual at most %s, estimated from the instances", formatSummaryValue(*r.Value))
+		case r.Value != nil:
+			detail = fmt.Sprintf("actual %s", formatSummaryValue(*r.Value))
+		}
+		if _, err := fmt.Fprintf(w, "%s  %s: %s (%s)\n", status, r.Metric, r.Expression, detail); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// failedThresholds returns the failed threshold results.
+func failedThresholds(results []ThresholdResult) []ThresholdResult {
+	var rv []ThresholdResult
+	for _, r := range results {
+		if !r.Passed {
+			rv = append(rv, r)
+		}
+	}
+	return rv
+}
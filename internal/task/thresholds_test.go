@@ -0,0 +1,116 @@
+package task
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Azure/k6ctl/internal/stdlib"
+)
+
+func TestParseThresholdExpression(t *testing.T) {
+	cases := []struct {
+		expr      string
+		expected  thresholdExpression
+		expectErr bool
+	}{
+		{expr: "p(95)<500", expected: thresholdExpression{Aggregation: "p(95)", Operator: "<", Value: 500}},
+		{expr: " p( 99.9 ) <= 1.5 ", expected: thresholdExpression{Aggregation: "p(99.9)", Operator: "<=", Value: 1.5}},
+		{expr: "rate>0.99", expected: thresholdExpression{Aggregation: "rate", Operator: ">", Value: 0.99}},
+		{expr: "count===100", expected: thresholdExpression{Aggregation: "count", Operator: "===", Value: 100}},
+		{expr: "avg", expectErr: true},
+		{expr: "avg<fast", expectErr: true},
+		{expr: "P95 < 200", expectErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expr, func(t *testing.T) {
+			actual, err := parseThresholdExpression(tc.expr)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestValidateThresholds(t *testing.T) {
+	assert.NoError(t, ValidateThresholds(nil))
+	assert.NoError(t, ValidateThresholds(map[string][]string{"http_reqs": {"count>100"}}))
+	assert.ErrorContains(t, ValidateThresholds(map[string][]string{"http_reqs": {"count"}}), "thresholds.http_reqs")
+}
+
+func TestEvaluateThresholds(t *testing.T) {
+	merged := MergeSummaries([]*Summary{
+		loadTestSummary(t, "instance-0.json"),
+		loadTestSummary(t, "instance-1.json"),
+	})
+
+	results := EvaluateThresholds(merged, map[string][]string{
+		"checks":            {"rate>0.99"},
+		"http_reqs":         {"count>150"},
+		"http_req_duration": {"p(95)<500", "p(95)>100", "p(99)<1000", "max>=600"},
+		"data_sent":         {"count<1"},
+	})
+
+	assert.Equal(t, []ThresholdResult{
+		{Metric: "checks", Expression: "rate>0.99", Value: stdlib.Ptr(0.95)},
+		{Metric: "data_sent", Expression: "count<1", Error: "metric not found in the summary"},
+		{Metric: "http_req_duration", Expression: "max>=600", Value: stdlib.Ptr(600.0), Passed: true},
+		{Metric: "http_req_duration", Expression: "p(95)<500", Value: stdlib.Ptr(550.0), UpperBound: true},
+		{
+			Metric:     "http_req_duration",
+			Expression: "p(95)>100",
+			Error:      "p(95) merged from the instances is an upper bound, only < and <= thresholds can be evaluated",
+		},
+		{
+			Metric:     "http_req_duration",
+			Expression: "p(99)<1000",
+			Error:      "p(99) is not in the summary, add it to the summaryTrendStats option",
+		},
+		{Metric: "http_reqs", Expression: "count>150", Value: stdlib.Ptr(200.0), Passed: true},
+	}, results)
+
+	// results are recorded in the summary
+	assert.Equal(
+		t,
+		map[string]bool{"max>=600": false, "p(95)<500": true, "p(95)>100": true, "p(99)<1000": true},
+		merged.Metrics["http_req_duration"].Thresholds,
+	)
+	assert.Equal(t, map[string]bool{"count>150": false}, merged.Metrics["http_reqs"].Thresholds)
+
+	var out bytes.Buffer
+	assert.NoError(t, PrintThresholdResults(&out, []ThresholdResult{results[3], results[6]}))
+	assert.Equal(
+		t,
+		"FAIL  http_req_duration: p(95)<500 (actual at most 550, estimated from the instances)\n"+
+			"PASS  http_reqs: count>150 (actual 200)\n",
+		out.String(),
+	)
+}
+
+func TestResolveThresholdsResult(t *testing.T) {
+	passed := []ThresholdResult{{Metric: "http_reqs", Expression: "count>1", Passed: true}}
+	failed := []ThresholdResult{{Metric: "http_req_duration", Expression: "p(95)<500"}}
+	thresholdsErr := &ThresholdsFailedError{Pods: []FailedPod{{Name: "pod-0", ExitCode: stdlib.Ptr[int32](99)}}}
+	taskErr := &TaskFailedError{Job: "job", Pods: []FailedPod{{Name: "pod-0", ExitCode: stdlib.Ptr[int32](107)}}}
+
+	// thresholds across all instances take precedence over the ones of single instances
+	assert.NoError(t, resolveThresholdsResult(nil, passed))
+	assert.NoError(t, resolveThresholdsResult(thresholdsErr, passed))
+
+	var actual *ThresholdsFailedError
+	err := resolveThresholdsResult(nil, failed)
+	if assert.ErrorAs(t, err, &actual) {
+		assert.Equal(t, failed, actual.Thresholds)
+		assert.Equal(t, 99, actual.ExitCode())
+		assert.Equal(t, "thresholds failed across all instances: http_req_duration: p(95)<500", err.Error())
+	}
+
+	// other failures are reported as is
+	assert.True(t, errors.Is(resolveThresholdsResult(taskErr, passed), taskErr))
+}
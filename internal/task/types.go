@@ -1,39 +1,134 @@
 package task
 
 type Schema struct {
-	Version string           `json:"version"`
-	Name    string           `json:"name"`
+	Version string           `json:"version" jsonschema:"enum=v1"`
+	Name    string           `json:"name" jsonschema:"required"`
 	Files   []FileMount      `json:"files"`
 	Configs []ConfigProvider `json:"configs"`
 	K6      K6               `json:"k6"`
+	// Thresholds - extra k6 thresholds by metric name, evaluated against the metrics of all instances.
+	Thresholds map[string][]string `json:"thresholds"`
+	// Vendor - settings for vendoring the remote modules imported by the scripts.
+	Vendor Vendor `json:"vendor"`
 }
 
+// FileMount mounts local files to the scripts volume.
 type FileMount struct {
-	Source string `json:"source"`
-	Dest   string `json:"dest"`
+	// Source - a file, a directory or a glob like lib/**/*.js, relative to the base directory.
+	Source string `json:"source" jsonschema:"required"`
+	// Dest - the path of the file, or the directory prefix of the files of a directory or glob.
+	// Defaults to the source path, or the leading directory of the glob.
+	Dest string `json:"dest"`
+	// Exclude - glob patterns of the files to skip, relative to the source directory.
+	// Patterns without slash match the file names at any depth.
+	Exclude []string `json:"exclude"`
+	// Binary - mount the files as binary data. Files which are not valid UTF-8 are mounted as binary data anyway.
+	Binary bool `json:"binary"`
+}
+
+// Vendor defines how the remote modules imported by the scripts are vendored.
+// When enabled, the modules are fetched on the client and mounted with the scripts, with the imports
+// rewritten to them, so the pods don't need internet egress.
+type Vendor struct {
+	Enabled bool `json:"enabled"`
+	// CacheDir - the local directory caching the fetched modules. Defaults to k6ctl/modules in the user cache directory.
+	CacheDir string `json:"cacheDir"`
+	// LockFile - the file recording the checksums of the modules, relative to the base directory.
+	// Defaults to k6ctl.lock.json.
+	LockFile string `json:"lockFile"`
 }
 
 type ConfigProviderProviderSpec struct {
-	Name   string         `json:"name"`
+	Name   string         `json:"name" jsonschema:"required"`
 	Params map[string]any `json:"params"`
 }
 
 type ConfigProvider struct {
-	Provider ConfigProviderProviderSpec `json:"provider"`
-	Env      string                     `json:"env"`
+	Provider ConfigProviderProviderSpec `json:"provider" jsonschema:"required"`
+	Env      string                     `json:"env" jsonschema:"required"`
 }
 
 type K6 struct {
-	Namespace      string           `json:"namespace"`
-	PodImage       string           `json:"image"`
-	ControllerKind string           `json:"controllerKind"`
-	JobSpec        struct{}         `json:"jobSpec"`
-	PodSpec        struct{}         `json:"podSpec"`
-	ConfigPlugins  []K6ConfigPlugin `json:"configPlugins"`
+	Namespace      string `json:"namespace" jsonschema:"required"`
+	PodImage       string `json:"image" jsonschema:"required"`
+	ControllerKind string `json:"controllerKind" jsonschema:"enum=Job,enum=CronJob"`
+	// Distribution - how the load is distributed across instances: replicate (default) or segments.
+	Distribution string `json:"distribution" jsonschema:"enum=replicate,enum=segments"`
+	// JobSpec - partial batch/v1 JobSpec to strategic-merge-patch over the generated job spec.
+	JobSpec map[string]any `json:"jobSpec"`
+	// PodSpec - partial core/v1 PodSpec to strategic-merge-patch over the generated pod spec.
+	PodSpec       map[string]any   `json:"podSpec"`
+	ConfigPlugins []K6ConfigPlugin `json:"configPlugins"`
+	// CronJob - settings for the CronJob controller kind.
+	CronJob K6CronJob `json:"cronJob"`
+	// StartBarrier - settings for starting all instances together.
+	StartBarrier K6StartBarrier `json:"startBarrier"`
+	// Placement - how the instances are placed across the cluster.
+	Placement K6Placement `json:"placement"`
+	// Summary - settings for collecting the end-of-test summary of the instances.
+	Summary K6Summary `json:"summary"`
+	// Assets - settings for delivering the files too large for ConfigMaps.
+	Assets K6Assets `json:"assets"`
+	// ExpectedDuration - how long the test is expected to run, e.g. 30m. Together with the time for starting
+	// the instances, it is the default expiry of the tokens created by the serviceAccountToken config provider.
+	ExpectedDuration string `json:"expectedDuration"`
+}
+
+// K6CronJob defines the settings for running the task as a CronJob.
+type K6CronJob struct {
+	Schedule                   string `json:"schedule"`
+	TimeZone                   string `json:"timeZone"`
+	ConcurrencyPolicy          string `json:"concurrencyPolicy" jsonschema:"enum=Allow,enum=Forbid,enum=Replace"`
+	Suspend                    bool   `json:"suspend"`
+	SuccessfulJobsHistoryLimit *int32 `json:"successfulJobsHistoryLimit"`
+	FailedJobsHistoryLimit     *int32 `json:"failedJobsHistoryLimit"`
+}
+
+// K6StartBarrier defines the settings for synchronising the start of all instances.
+// When enabled, the instances start in paused state and are resumed together once all of them are ready.
+type K6StartBarrier struct {
+	Enabled bool `json:"enabled"`
+	// Timeout - how long to wait for all instances to be ready, e.g. 5m. Defaults to 5m.
+	Timeout string `json:"timeout"`
+}
+
+// K6Placement defines how the instances of a run are placed across the cluster.
+type K6Placement struct {
+	// Mode - required (default), preferred, spread or none.
+	Mode string `json:"mode" jsonschema:"enum=required,enum=preferred,enum=spread,enum=none"`
+	// TopologyKey - the node label to spread the instances by. Defaults to kubernetes.io/hostname.
+	TopologyKey string `json:"topologyKey"`
+	// MaxSkew - the max skew of the topology spread constraint in spread mode. Defaults to 1.
+	MaxSkew int32 `json:"maxSkew"`
+	// WhenUnsatisfiable - DoNotSchedule (default) or ScheduleAnyway in spread mode.
+	WhenUnsatisfiable string `json:"whenUnsatisfiable" jsonschema:"enum=DoNotSchedule,enum=ScheduleAnyway"`
+}
+
+// K6Summary defines the settings for collecting the end-of-test summaries.
+// When enabled, the summaries of all instances are merged, printed and saved to a local file
+// after the run finished. It requires a shell (sh) in the k6 image.
+type K6Summary struct {
+	Enabled bool `json:"enabled"`
+	// File - the local file to save the merged summary to. Defaults to k6ctl-summary-<name>-<run-id>.json.
+	File string `json:"file"`
+}
+
+// K6Assets defines how the files too large for ConfigMaps are delivered to the instances.
+// Such files are uploaded by k6ctl to an init container of each instance as a compressed archive,
+// so they are not supported with the CronJob controller kind.
+type K6Assets struct {
+	// MaxConfigMapFileSize - files larger than this are uploaded instead of mounted from ConfigMaps, e.g. 512Ki.
+	// Defaults to and can't exceed 1000Ki.
+	MaxConfigMapFileSize string `json:"maxConfigMapFileSize"`
+	// Image - the image of the init container receiving the uploaded files. It requires sh and tar.
+	// Defaults to the k6 image.
+	Image string `json:"image"`
+	// UploadTimeout - how long to wait for the instances to receive the uploaded files, e.g. 5m. Defaults to 5m.
+	UploadTimeout string `json:"uploadTimeout"`
 }
 
 type K6ConfigPlugin struct {
-	Namespace  string   `json:"namespace"`
+	Namespace  string   `json:"namespace" jsonschema:"required"`
 	BinaryPath string   `json:"binaryPath"`
 	Args       []string `json:"args"`
 }
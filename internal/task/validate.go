@@ -0,0 +1,371 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// SchemaError is a problem found in the task config.
+type SchemaError struct {
+	// File is the path of the task config file. It's empty if the config is not loaded from a file.
+	File string
+	// Line and Column are the 1-based position of the problem. They're 0 if the position is unknown.
+	Line   int
+	Column int
+	// Path is the path of the field in the task config, e.g. configs[0].env.
+	Path    string
+	Message string
+}
+
+func (e *SchemaError) Error() string {
+	var location []string
+	if e.File != "" {
+		location = append(location, e.File)
+	}
+	if e.Line > 0 {
+		location = append(location, strconv.Itoa(e.Line), strconv.Itoa(e.Column))
+	}
+
+	msg := e.Message
+	if e.Path != "" {
+		msg = fmt.Sprintf("%s: %s", e.Path, e.Message)
+	}
+	if len(location) == 0 {
+		return msg
+	}
+	return fmt.Sprintf("%s: %s", strings.Join(location, ":"), msg)
+}
+
+// SchemaErrors are the problems found in the task config.
+type SchemaErrors []*SchemaError
+
+func (e SchemaErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// decodeErrorPattern matches the position prefix of the goccy/go-yaml errors, e.g. [3:5] unknown field "foo".
+var decodeErrorPattern = regexp.MustCompile(`^\[(\d+):(\d+)\] (.*)$`)
+
+// newDecodeSchemaError converts the YAML decoding error to SchemaError.
+func newDecodeSchemaError(file string, err error) *SchemaError {
+	firstLine, _, _ := strings.Cut(err.Error(), "\n")
+	rv := &SchemaError{
+		File:    file,
+		Message: strings.TrimSpace(firstLine),
+	}
+	if m := decodeErrorPattern.FindStringSubmatch(rv.Message); m != nil {
+		rv.Line, _ = strconv.Atoi(m[1])
+		rv.Column, _ = strconv.Atoi(m[2])
+		rv.Message = m[3]
+	}
+	return rv
+}
+
+// decodeSchema decodes the task config strictly: unknown fields and duplicate keys are rejected.
+// Configs of older schema versions are converted to LatestSchemaVersion.
+func decodeSchema(file string, source []byte) (*Schema, error) {
+	f, err := parser.ParseBytes(source, 0)
+	if err != nil {
+		return nil, SchemaErrors{newDecodeSchemaError(file, err)}
+	}
+	doc, schemaErr := schemaDocument(file, f)
+	if schemaErr != nil {
+		return nil, SchemaErrors{schemaErr}
+	}
+	if _, schemaErr := migrateSchemaDocument(file, doc); schemaErr != nil {
+		return nil, SchemaErrors{schemaErr}
+	}
+
+	rv := new(Schema)
+	if err := yaml.NodeToValue(
+		doc,
+		rv,
+		yaml.UseJSONUnmarshaler(),
+		yaml.DisallowUnknownField(),
+		yaml.DisallowDuplicateKey(),
+	); err != nil {
+		return nil, SchemaErrors{newDecodeSchemaError(file, err)}
+	}
+	rv.Version = LatestSchemaVersion
+
+	return rv, nil
+}
+
+// ValidateSchemaFile loads the task config file strictly and validates its semantics.
+// Relative file sources are resolved against baseDir.
+// The problems are reported as SchemaErrors with their positions in the file.
+func ValidateSchemaFile(path string, baseDir string) (*Schema, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := decodeSchema(path, source)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldErrs := validateSchema(schema, baseDir)
+	if len(fieldErrs) == 0 {
+		return schema, nil
+	}
+
+	file, err := parser.ParseBytes(source, 0)
+	if err != nil {
+		// the source has been decoded, so this is not expected
+		file = nil
+	}
+	rv := make(SchemaErrors, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		schemaErr := &SchemaError{
+			File:    path,
+			Path:    fe.Path,
+			Message: fe.Message,
+		}
+		if file != nil {
+			schemaErr.Line, schemaErr.Column = lookupFieldPosition(file, fe.Path)
+		}
+		rv = append(rv, schemaErr)
+	}
+	return nil, rv
+}
+
+// lookupFieldPosition finds the position of the field in the YAML AST.
+// If the field is absent, the position of the closest parent is used.
+func lookupFieldPosition(file *ast.File, fieldPath string) (int, int) {
+	segments := splitFieldPath(fieldPath)
+	for i := len(segments); i > 0; i-- {
+		p, err := yaml.PathString("$" + strings.Join(segments[:i], ""))
+		if err != nil {
+			continue
+		}
+		node, err := p.FilterFile(file)
+		if err != nil || node == nil {
+			continue
+		}
+		if line, column := nodePosition(node); line > 0 {
+			return line, column
+		}
+	}
+
+	// fallback to the start of the document
+	if len(file.Docs) > 0 && file.Docs[0].Body != nil {
+		return nodePosition(file.Docs[0].Body)
+	}
+	return 0, 0
+}
+
+// nodePosition returns the position of the node. The token of a mapping is its first colon,
+// so the position of its first key is used instead.
+func nodePosition(node ast.Node) (int, int) {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		if len(n.Values) > 0 {
+			return nodePosition(n.Values[0].Key)
+		}
+	case *ast.MappingValueNode:
+		return nodePosition(n.Key)
+	}
+
+	tk := node.GetToken()
+	if tk == nil || tk.Position == nil {
+		return 0, 0
+	}
+	return tk.Position.Line, tk.Position.Column
+}
+
+// fieldPathSegmentPattern matches the segments of a field path: .name or [index].
+var fieldPathSegmentPattern = regexp.MustCompile(`\.?[^.\[]+|\[\d+\]`)
+
+// splitFieldPath splits the field path like configs[0].env to the path segments .configs, [0], .env.
+func splitFieldPath(fieldPath string) []string {
+	var rv []string
+	for _, s := range fieldPathSegmentPattern.FindAllString(fieldPath, -1) {
+		if !strings.HasPrefix(s, ".") && !strings.HasPrefix(s, "[") {
+			s = "." + s
+		}
+		rv = append(rv, s)
+	}
+	return rv
+}
+
+// schemaFieldError is a semantic problem of a task config field.
+type schemaFieldError struct {
+	Path    string
+	Message string
+}
+
+// envNamePattern matches the valid environment variable names.
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateSchema validates the semantics of the task config.
+func validateSchema(schema *Schema, baseDir string) []schemaFieldError {
+	var errs []schemaFieldError
+	add := func(path string, format string, args ...any) {
+		errs = append(errs, schemaFieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	addErr := func(path string, err error) {
+		if err == nil {
+			return
+		}
+		add(path, "%s", err)
+	}
+
+	if schema.Name == "" {
+		add("name", "is required")
+	} else if msgs := validation.IsDNS1123Label(schema.Name); len(msgs) > 0 {
+		add("name", "invalid name %q: %s", schema.Name, strings.Join(msgs, ", "))
+	}
+
+	if schema.K6.Namespace == "" {
+		add("k6.namespace", "is required")
+	} else if msgs := validation.IsDNS1123Label(schema.K6.Namespace); len(msgs) > 0 {
+		add("k6.namespace", "invalid namespace %q: %s", schema.K6.Namespace, strings.Join(msgs, ", "))
+	}
+	if schema.K6.PodImage == "" {
+		add("k6.image", "is required")
+	}
+
+	addErr("k6.controllerKind", validateControllerKind(schema.K6))
+	addErr("k6.distribution", validateDistribution(schema.K6.Distribution))
+	addErr("k6.placement", validatePlacement(schema.K6.Placement))
+	if schema.K6.StartBarrier.Timeout != "" {
+		if _, err := time.ParseDuration(schema.K6.StartBarrier.Timeout); err != nil {
+			add("k6.startBarrier.timeout", "invalid duration %q", schema.K6.StartBarrier.Timeout)
+		}
+	}
+	if schema.K6.Assets.MaxConfigMapFileSize != "" {
+		if _, err := parseMaxConfigMapFileSize(schema.K6.Assets.MaxConfigMapFileSize); err != nil {
+			add("k6.assets.maxConfigMapFileSize", "%s", err)
+		}
+	}
+	if schema.K6.Assets.UploadTimeout != "" {
+		if d, err := time.ParseDuration(schema.K6.Assets.UploadTimeout); err != nil || d <= 0 {
+			add("k6.assets.uploadTimeout", "invalid duration %q", schema.K6.Assets.UploadTimeout)
+		}
+	}
+	if schema.K6.ExpectedDuration != "" {
+		if d, err := time.ParseDuration(schema.K6.ExpectedDuration); err != nil || d <= 0 {
+			add("k6.expectedDuration", "invalid duration %q", schema.K6.ExpectedDuration)
+		}
+	}
+	if err := validateSpecOverrides(schema.K6); err != nil {
+		for _, e := range unjoinErrors(err) {
+			// the messages are prefixed by the paths of the fields
+			path, msg, ok := strings.Cut(e.Error(), ": ")
+			if !ok {
+				path, msg = "k6", e.Error()
+			}
+			add(path, "%s", msg)
+		}
+	}
+	for idx, plugin := range schema.K6.ConfigPlugins {
+		// the binary path defaults to k6ctl-<namespace> from $PATH
+		if plugin.Namespace == "" {
+			add(fmt.Sprintf("k6.configPlugins[%d].namespace", idx), "is required")
+		}
+	}
+
+	mountedBy := map[string]int{}
+	for idx, file := range schema.Files {
+		path := fmt.Sprintf("files[%d]", idx)
+		numErrs := len(errs)
+		if file.Dest != "" {
+			addErr(path+".dest", validateDestPath(file.Dest))
+		}
+		for excludeIdx, exclude := range file.Exclude {
+			addErr(fmt.Sprintf("%s.exclude[%d]", path, excludeIdx), validatePathPattern(exclude))
+		}
+		if file.Source == "" {
+			add(path+".source", "is required")
+		}
+		if len(errs) > numErrs || baseDir == "" {
+			// the files can't be listed
+			continue
+		}
+
+		files, err := resolveFileMount(baseDir, file)
+		if err != nil {
+			addErr(path+".source", err)
+			continue
+		}
+		for _, f := range files {
+			if first, ok := mountedBy[f.Path]; ok {
+				add(path+".dest", "%q is already mounted by files[%d]", f.Path, first)
+				break
+			}
+			mountedBy[f.Path] = idx
+		}
+	}
+
+	envIndexes := map[string]int{}
+	for idx, c := range schema.Configs {
+		path := fmt.Sprintf("configs[%d]", idx)
+		switch {
+		case c.Provider.Name == "":
+			add(path+".provider.name", "is required")
+		case c.Provider.Name == configProviderNameServiceAccountToken && schema.K6.ControllerKind == controllerKindCronJob:
+			add(
+				path+".provider.name",
+				"%s is not supported with controllerKind %q, the token expires before the later runs",
+				c.Provider.Name, controllerKindCronJob,
+			)
+		}
+
+		switch {
+		case c.Env == "":
+			add(path+".env", "is required")
+		case !envNamePattern.MatchString(c.Env):
+			add(path+".env", "invalid env name %q, expected letters, digits and underscores, not starting with a digit", c.Env)
+		default:
+			if first, ok := envIndexes[c.Env]; ok {
+				add(path+".env", "duplicate env %q, already set by configs[%d]", c.Env, first)
+			} else {
+				envIndexes[c.Env] = idx
+			}
+		}
+	}
+
+	metrics := make([]string, 0, len(schema.Thresholds))
+	for metric := range schema.Thresholds {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+	for _, metric := range metrics {
+		for idx, expr := range schema.Thresholds[metric] {
+			if _, err := parseThresholdExpression(expr); err != nil {
+				add(fmt.Sprintf("thresholds.%s[%d]", metric, idx), "%s", err)
+			}
+		}
+	}
+
+	return errs
+}
+
+// unjoinErrors returns the errors joined by errors.Join.
+func unjoinErrors(err error) []error {
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		var rv []error
+		for _, e := range joined.Unwrap() {
+			rv = append(rv, unjoinErrors(e)...)
+		}
+		return rv
+	}
+	return []error{err}
+}
@@ -0,0 +1,156 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTaskConfig(t *testing.T, content string) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "script.js"), []byte("export default function () {}"), 0o644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "lib"), 0o755))
+	p := filepath.Join(dir, "k6ctl.yaml")
+	assert.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	return p, dir
+}
+
+func TestValidateSchemaFile(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		p, dir := writeTaskConfig(t, `name: test
+files:
+- source: script.js
+  dest: script.js
+k6:
+  namespace: default
+  image: grafana/k6
+configs:
+- provider:
+    name: parameter
+  env: MESSAGE
+thresholds:
+  http_reqs:
+  - count>10
+`)
+		schema, err := ValidateSchemaFile(p, dir)
+		assert.NoError(t, err)
+		assert.Equal(t, "test", schema.Name)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		p, dir := writeTaskConfig(t, `name: test
+k6:
+  namespace: default
+  imag: grafana/k6
+`)
+		_, err := ValidateSchemaFile(p, dir)
+
+		var schemaErrs SchemaErrors
+		if assert.ErrorAs(t, err, &schemaErrs) && assert.Len(t, schemaErrs, 1) {
+			assert.Equal(t, 4, schemaErrs[0].Line)
+			assert.Equal(t, 3, schemaErrs[0].Column)
+			assert.Equal(t, p+`:4:3: unknown field "imag"`, schemaErrs[0].Error())
+		}
+	})
+
+	t.Run("duplicate key", func(t *testing.T) {
+		p, dir := writeTaskConfig(t, "name: test\nname: test2\n")
+		_, err := ValidateSchemaFile(p, dir)
+		assert.ErrorContains(t, err, p+`:2:1: duplicate key "name"`)
+	})
+
+	t.Run("semantic errors", func(t *testing.T) {
+		p, dir := writeTaskConfig(t, `files:
+- source: ../script.js
+  dest: script.js
+- source: lib
+  dest: script.js
+- source: missing.js
+  dest: ../b.js
+  exclude: ["[a"]
+k6:
+  namespace: default
+  distribution: random
+configs:
+- provider:
+    name: parameter
+  env: MESSAGE
+- provider:
+    name: parameter
+  env: MESSAGE
+- provider: {}
+  env: 1MESSAGE
+thresholds:
+  http_reqs:
+  - count
+`)
+		_, err := ValidateSchemaFile(p, dir)
+
+		var schemaErrs SchemaErrors
+		if !assert.ErrorAs(t, err, &schemaErrs) {
+			return
+		}
+		type location struct {
+			Path   string
+			Line   int
+			Column int
+		}
+		var actual []location
+		for _, e := range schemaErrs {
+			assert.Equal(t, p, e.File)
+			actual = append(actual, location{Path: e.Path, Line: e.Line, Column: e.Column})
+		}
+		assert.Equal(t, []location{
+			{Path: "name", Line: 1, Column: 1},
+			{Path: "k6.image", Line: 10, Column: 3},
+			{Path: "k6.distribution", Line: 11, Column: 17},
+			{Path: "files[0].source", Line: 2, Column: 11},
+			{Path: "files[1].source", Line: 4, Column: 11},
+			{Path: "files[2].dest", Line: 7, Column: 9},
+			{Path: "files[2].exclude[0]", Line: 8, Column: 13},
+			{Path: "configs[1].env", Line: 18, Column: 8},
+			{Path: "configs[2].provider.name", Line: 19, Column: 13},
+			{Path: "configs[2].env", Line: 20, Column: 8},
+			{Path: "thresholds.http_reqs[0]", Line: 23, Column: 5},
+		}, actual)
+	})
+
+	t.Run("service account token with CronJob", func(t *testing.T) {
+		p, dir := writeTaskConfig(t, `name: test
+k6:
+  namespace: default
+  image: grafana/k6
+  controllerKind: CronJob
+  cronJob:
+    schedule: "@daily"
+configs:
+- provider:
+    name: serviceAccountToken
+    params:
+      namespace: default
+      serviceAccount: k6
+  env: SA_TOKEN
+`)
+		_, err := ValidateSchemaFile(p, dir)
+
+		var schemaErrs SchemaErrors
+		if assert.ErrorAs(t, err, &schemaErrs) && assert.Len(t, schemaErrs, 1) {
+			assert.Equal(t, "configs[0].provider.name", schemaErrs[0].Path)
+			assert.Equal(t, 10, schemaErrs[0].Line)
+			assert.ErrorContains(t, schemaErrs[0], `serviceAccountToken is not supported with controllerKind "CronJob"`)
+		}
+	})
+}
+
+func TestLoadSchema_Strict(t *testing.T) {
+	_, err := LoadSchemaFromFile("./testdata/integration/k6ctl.yaml")
+	assert.NoError(t, err)
+
+	p, _ := writeTaskConfig(t, "name: test\nunknown: true\n")
+	_, err = LoadSchemaFromFile(p)
+	assert.ErrorContains(t, err, p+`:2:1: unknown field "unknown"`)
+}
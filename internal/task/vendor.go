@@ -0,0 +1,427 @@
+package task
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// vendorDir is the directory of the vendored remote modules in the scripts volume.
+	vendorDir = "_vendor"
+	// defaultVendorLockFile is the lock file of the vendored modules, relative to the base directory.
+	defaultVendorLockFile = "k6ctl.lock.json"
+)
+
+// FetchRemoteModule downloads the remote module at the URL.
+type FetchRemoteModule func(ctx context.Context, moduleURL string) ([]byte, error)
+
+// fetchRemoteModuleHTTP downloads the remote module with an HTTP GET request.
+func fetchRemoteModuleHTTP(ctx context.Context, moduleURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, moduleURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// vendorSettings are the resolved vendor settings.
+type vendorSettings struct {
+	Enabled bool
+	// CacheDir is the absolute path of the directory caching the fetched modules.
+	CacheDir string
+	// LockFile is the absolute path of the lock file.
+	LockFile string
+}
+
+// resolveVendor resolves the vendor settings from the run options and the task config.
+// Relative paths are resolved against the base directory.
+func resolveVendor(opt *runTaskOption, vendor Vendor, baseDir string) (vendorSettings, error) {
+	if !opt.Vendor && !vendor.Enabled {
+		return vendorSettings{}, nil
+	}
+
+	rv := vendorSettings{
+		Enabled:  true,
+		CacheDir: vendor.CacheDir,
+		LockFile: vendor.LockFile,
+	}
+	if rv.CacheDir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return vendorSettings{}, fmt.Errorf("vendor.cacheDir is required as the user cache directory is unknown: %w", err)
+		}
+		rv.CacheDir = filepath.Join(userCacheDir, "k6ctl", "modules")
+	} else if !filepath.IsAbs(rv.CacheDir) {
+		rv.CacheDir = filepath.Join(baseDir, rv.CacheDir)
+	}
+	if rv.LockFile == "" {
+		rv.LockFile = defaultVendorLockFile
+	}
+	if !filepath.IsAbs(rv.LockFile) {
+		rv.LockFile = filepath.Join(baseDir, rv.LockFile)
+	}
+	return rv, nil
+}
+
+// vendorLock records the checksums of the vendored modules, so runs use the same module contents.
+type vendorLock struct {
+	Modules map[string]vendorLockEntry `json:"modules"`
+}
+
+type vendorLockEntry struct {
+	// SHA256 is the hex encoded SHA-256 checksum of the module content.
+	SHA256 string `json:"sha256"`
+}
+
+// loadVendorLock loads the lock file. A missing lock file is empty.
+func loadVendorLock(file string) (*vendorLock, error) {
+	rv := &vendorLock{Modules: map[string]vendorLockEntry{}}
+
+	b, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return rv, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read lock file: %w", err)
+	}
+	if err := json.Unmarshal(b, rv); err != nil {
+		return nil, fmt.Errorf("parse lock file %q: %w", file, err)
+	}
+	if rv.Modules == nil {
+		rv.Modules = map[string]vendorLockEntry{}
+	}
+	return rv, nil
+}
+
+// save writes the lock file with the modules sorted by URL.
+func (l *vendorLock) save(file string) error {
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write lock file: %w", err)
+	}
+	return nil
+}
+
+// moduleSpecifier is a module specifier of an import in a script source.
+type moduleSpecifier struct {
+	// Start and End are the offsets of the specifier in the source, excluding the quotes.
+	Start int
+	End   int
+	Value string
+}
+
+// scanModuleSpecifiers returns the specifiers of the imports, re-exports and require calls in the source,
+// sorted by offset.
+func scanModuleSpecifiers(source string) []moduleSpecifier {
+	masked := maskScript(source)
+
+	var rv []moduleSpecifier
+	seen := map[int]bool{}
+	for _, pattern := range importPatterns {
+		for _, m := range pattern.FindAllStringSubmatchIndex(masked, -1) {
+			if seen[m[2]] {
+				continue
+			}
+			seen[m[2]] = true
+			rv = append(rv, moduleSpecifier{Start: m[2], End: m[3], Value: source[m[2]:m[3]]})
+		}
+	}
+	sort.Slice(rv, func(i, j int) bool {
+		return rv[i].Start < rv[j].Start
+	})
+	return rv
+}
+
+// rewriteModuleSpecifiers replaces the specifiers for which replace returns true.
+// It returns nil if no specifier is replaced.
+func rewriteModuleSpecifiers(
+	source string,
+	specifiers []moduleSpecifier,
+	replace func(specifier string) (string, bool),
+) []byte {
+	var (
+		b        strings.Builder
+		last     int
+		replaced bool
+	)
+	for _, s := range specifiers {
+		v, ok := replace(s.Value)
+		if !ok || v == s.Value {
+			continue
+		}
+		b.WriteString(source[last:s.Start])
+		b.WriteString(v)
+		last = s.End
+		replaced = true
+	}
+	if !replaced {
+		return nil
+	}
+	b.WriteString(source[last:])
+	return []byte(b.String())
+}
+
+// isRemoteModule reports whether the specifier imports a module by URL.
+func isRemoteModule(specifier string) bool {
+	return strings.HasPrefix(specifier, "https://") || strings.HasPrefix(specifier, "http://")
+}
+
+// vendorModulePath returns the path of the vendored module in the scripts volume, which mirrors the URL
+// under the vendor directory. Modules with a query are suffixed with its checksum.
+func vendorModulePath(u *url.URL) string {
+	p := u.Path
+	if p == "" || strings.HasSuffix(p, "/") {
+		p += "index.js"
+	}
+	rv := path.Join(vendorDir, u.Host, path.Clean("/"+p))
+	if u.RawQuery != "" {
+		sum := sha256.Sum256([]byte(u.RawQuery))
+		ext := path.Ext(rv)
+		rv = fmt.Sprintf("%s-%s%s", strings.TrimSuffix(rv, ext), hex.EncodeToString(sum[:4]), ext)
+	}
+	return rv
+}
+
+// relativeModuleSpecifier returns the relative specifier importing the target path from the script path.
+func relativeModuleSpecifier(from string, target string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(from)), filepath.FromSlash(target))
+	if err != nil {
+		return target
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return rel
+}
+
+// remoteModuleVendor fetches the remote modules imported by the scripts and rewrites the imports
+// to the vendored modules.
+type remoteModuleVendor struct {
+	settings vendorSettings
+	fetch    FetchRemoteModule
+	lock     *vendorLock
+
+	lockChanged bool
+	// fetched counts the modules not found in the cache.
+	fetched int
+}
+
+// cachePath returns the path of the cached module content.
+func (v *remoteModuleVendor) cachePath(moduleURL string) string {
+	sum := sha256.Sum256([]byte(moduleURL))
+	return filepath.Join(v.settings.CacheDir, hex.EncodeToString(sum[:]))
+}
+
+// load returns the content of the module, from the cache if it matches the lock file.
+// Fetched modules are cached, and recorded in the lock file if absent.
+func (v *remoteModuleVendor) load(ctx context.Context, moduleURL string) (string, error) {
+	entry, locked := v.lock.Modules[moduleURL]
+	cachePath := v.cachePath(moduleURL)
+
+	if b, err := os.ReadFile(cachePath); err == nil {
+		if !locked || checksum(b) == entry.SHA256 {
+			v.record(moduleURL, b)
+			return string(b), nil
+		}
+		// stale cache, the lock file is the source of truth
+	}
+
+	b, err := v.fetch(ctx, moduleURL)
+	if err != nil {
+		return "", fmt.Errorf("fetch %s: %w", moduleURL, err)
+	}
+	if locked && checksum(b) != entry.SHA256 {
+		return "", fmt.Errorf(
+			"checksum mismatch for %s: %s has %s, fetched %s",
+			moduleURL, v.settings.LockFile, entry.SHA256, checksum(b),
+		)
+	}
+	v.fetched++
+
+	if err := os.MkdirAll(v.settings.CacheDir, 0o755); err != nil {
+		return "", fmt.Errorf("create cache dir: %w", err)
+	}
+	// written to a temporary file first, so concurrent runs don't read partial content
+	tmp, err := os.CreateTemp(v.settings.CacheDir, ".fetch-")
+	if err != nil {
+		return "", fmt.Errorf("cache %s: %w", moduleURL, err)
+	}
+	_, err = tmp.Write(b)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), cachePath)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("cache %s: %w", moduleURL, err)
+	}
+
+	v.record(moduleURL, b)
+	return string(b), nil
+}
+
+// record adds the module to the lock file if absent.
+func (v *remoteModuleVendor) record(moduleURL string, b []byte) {
+	if _, ok := v.lock.Modules[moduleURL]; ok {
+		return
+	}
+	v.lock.Modules[moduleURL] = vendorLockEntry{SHA256: checksum(b)}
+	v.lockChanged = true
+}
+
+func checksum(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// vendor rewrites the remote imports of the mounted scripts to the vendored modules, and returns
+// the files with the rewritten scripts and the vendored modules. Remote modules are vendored transitively,
+// with their imports rewritten to the vendored paths.
+func (v *remoteModuleVendor) vendor(ctx context.Context, files []scriptFile) ([]scriptFile, error) {
+	type pendingModule struct {
+		URL          *url.URL
+		ReferencedBy string
+	}
+	var (
+		queue    []pendingModule
+		vendored = map[string]bool{}
+		rv       = make([]scriptFile, 0, len(files))
+	)
+	// enqueue returns the vendored path of the module, and vendors it if it's not yet.
+	enqueue := func(u *url.URL, referencedBy string) string {
+		u.Fragment = ""
+		p := vendorModulePath(u)
+		if !vendored[p] {
+			vendored[p] = true
+			queue = append(queue, pendingModule{URL: u, ReferencedBy: referencedBy})
+		}
+		return p
+	}
+
+	var scriptErr error
+	for _, f := range files {
+		if !scriptExtensions[path.Ext(f.Path)] || f.Binary {
+			rv = append(rv, f)
+			continue
+		}
+		b, err := f.read()
+		if err != nil {
+			return nil, err
+		}
+		source := string(b)
+		rewritten := rewriteModuleSpecifiers(source, scanModuleSpecifiers(source), func(specifier string) (string, bool) {
+			if !isRemoteModule(specifier) {
+				return "", false
+			}
+			u, err := url.Parse(specifier)
+			if err != nil {
+				scriptErr = errors.Join(scriptErr, fmt.Errorf("%s: invalid module URL %q: %w", f.Path, specifier, err))
+				return "", false
+			}
+			return relativeModuleSpecifier(f.Path, enqueue(u, f.Path)), true
+		})
+		if rewritten != nil {
+			f.Content = rewritten
+		}
+		rv = append(rv, f)
+	}
+	if scriptErr != nil {
+		return nil, scriptErr
+	}
+
+	for len(queue) > 0 {
+		m := queue[0]
+		queue = queue[1:]
+
+		moduleURL := m.URL.String()
+		source, err := v.load(ctx, moduleURL)
+		if err != nil {
+			return nil, fmt.Errorf("vendor module imported by %s: %w", m.ReferencedBy, err)
+		}
+
+		modulePath := vendorModulePath(m.URL)
+		// relative and root-relative imports are resolved against the module URL
+		rewritten := rewriteModuleSpecifiers(source, scanModuleSpecifiers(source), func(specifier string) (string, bool) {
+			if !isRemoteModule(specifier) && !strings.HasPrefix(specifier, "/") &&
+				!strings.HasPrefix(specifier, "./") && !strings.HasPrefix(specifier, "../") {
+				// bare specifiers like k6/http are provided by k6
+				return "", false
+			}
+			ref, err := url.Parse(specifier)
+			if err != nil {
+				return "", false
+			}
+			return relativeModuleSpecifier(modulePath, enqueue(m.URL.ResolveReference(ref), moduleURL)), true
+		})
+
+		module := scriptFile{Source: v.cachePath(moduleURL), Path: modulePath}
+		if rewritten != nil {
+			module.Content = rewritten
+		}
+		rv = append(rv, module)
+	}
+
+	return rv, nil
+}
+
+// vendorRemoteModules vendors the remote modules imported by the mounted scripts as configured,
+// and updates the lock file with the new modules.
+func (tr *taskRunner) vendorRemoteModules(ctx context.Context, files []scriptFile) ([]scriptFile, error) {
+	lock, err := loadVendorLock(tr.vendor.LockFile)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &remoteModuleVendor{
+		settings: tr.vendor,
+		fetch:    tr.fetchRemoteModule,
+		lock:     lock,
+	}
+	rv, err := v.vendor(ctx, files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to vendor remote modules: %w", err)
+	}
+
+	if v.lockChanged {
+		if err := lock.save(tr.vendor.LockFile); err != nil {
+			return nil, err
+		}
+	}
+	if vendored := len(rv) - len(files); vendored > 0 {
+		if _, err := fmt.Fprintf(
+			os.Stderr, "Vendored %d remote module(s) (%d fetched, %d cached) to %s/\n",
+			vendored, v.fetched, vendored-v.fetched, vendorDir,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return rv, nil
+}
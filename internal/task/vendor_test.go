@@ -0,0 +1,163 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanModuleSpecifiers(t *testing.T) {
+	source := `import http from "k6/http";
+import { uuidv4 } from 'https://jslib.k6.io/k6-utils/1.4.0/index.js';
+export * from "./lib.js";
+// import "https://example.com/commented.js";
+const m = require("https://example.com/required.js");
+`
+	var values []string
+	for _, s := range scanModuleSpecifiers(source) {
+		assert.Equal(t, s.Value, source[s.Start:s.End])
+		values = append(values, s.Value)
+	}
+	assert.Equal(t, []string{
+		"k6/http",
+		"https://jslib.k6.io/k6-utils/1.4.0/index.js",
+		"./lib.js",
+		"https://example.com/required.js",
+	}, values)
+
+	rewritten := rewriteModuleSpecifiers(source, scanModuleSpecifiers(source), func(specifier string) (string, bool) {
+		return "./vendored.js", isRemoteModule(specifier)
+	})
+	assert.Equal(t, `import http from "k6/http";
+import { uuidv4 } from './vendored.js';
+export * from "./lib.js";
+// import "https://example.com/commented.js";
+const m = require("./vendored.js");
+`, string(rewritten))
+
+	assert.Nil(t, rewriteModuleSpecifiers(source, scanModuleSpecifiers(source), func(string) (string, bool) {
+		return "", false
+	}))
+}
+
+func TestVendorModulePath(t *testing.T) {
+	cases := map[string]string{
+		"https://jslib.k6.io/k6-utils/1.4.0/index.js": "_vendor/jslib.k6.io/k6-utils/1.4.0/index.js",
+		"https://example.com/lib/":                    "_vendor/example.com/lib/index.js",
+		"https://example.com":                         "_vendor/example.com/index.js",
+		"https://example.com/../../etc/passwd":        "_vendor/example.com/etc/passwd",
+		"https://example.com/mod.js?v=2":              "_vendor/example.com/mod-269fc203.js",
+	}
+	for moduleURL, expected := range cases {
+		u, err := url.Parse(moduleURL)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, vendorModulePath(u), moduleURL)
+	}
+
+	assert.Equal(t, "./_vendor/example.com/a.js", relativeModuleSpecifier("run.js", "_vendor/example.com/a.js"))
+	assert.Equal(t, "../_vendor/example.com/a.js", relativeModuleSpecifier("lib/client.js", "_vendor/example.com/a.js"))
+	assert.Equal(t, "./b.js", relativeModuleSpecifier("_vendor/example.com/a.js", "_vendor/example.com/b.js"))
+}
+
+func TestTaskRunner_VendorRemoteModules(t *testing.T) {
+	modules := map[string]string{
+		"https://jslib.k6.io/k6-utils/1.4.0/index.js": `export function uuidv4() {}`,
+		"https://example.com/lib/a.js":                `import { b } from "./b.js"; import { c } from "/c.js"; export const a = b + c;`,
+		"https://example.com/lib/b.js":                `import http from "k6/http"; export const b = 1;`,
+		"https://example.com/c.js":                    `export const c = 2;`,
+	}
+
+	baseDir := t.TempDir()
+	writeFile := func(p string, content string) {
+		abs := filepath.Join(baseDir, filepath.FromSlash(p))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(abs), 0o755))
+		assert.NoError(t, os.WriteFile(abs, []byte(content), 0o644))
+	}
+	writeFile("run.js", `import { uuidv4 } from "https://jslib.k6.io/k6-utils/1.4.0/index.js"; import "./lib/client.js";`)
+	writeFile("lib/client.js", `import { a } from "https://example.com/lib/a.js";`)
+	cacheDir := t.TempDir()
+
+	run := func(t *testing.T, fetched *[]string) ([]scriptFile, error) {
+		tr := &taskRunner{
+			runID:         "run1",
+			sourceBaseDir: baseDir,
+			script:        "run.js",
+			taskConfig: &Schema{
+				Name:  "test",
+				Files: []FileMount{{Source: "run.js"}},
+				K6:    K6{Namespace: "test"},
+			},
+			vendor: vendorSettings{
+				Enabled:  true,
+				CacheDir: cacheDir,
+				LockFile: filepath.Join(baseDir, defaultVendorLockFile),
+			},
+			fetchRemoteModule: func(ctx context.Context, moduleURL string) ([]byte, error) {
+				*fetched = append(*fetched, moduleURL)
+				module, ok := modules[moduleURL]
+				if !ok {
+					return nil, fmt.Errorf("not found")
+				}
+				return []byte(module), nil
+			},
+		}
+		return tr.resolveScriptFiles(context.Background())
+	}
+
+	t.Run("fetch", func(t *testing.T) {
+		var fetched []string
+		files, err := run(t, &fetched)
+		assert.NoError(t, err)
+		assert.Len(t, fetched, 4)
+
+		contents := map[string]string{}
+		for _, f := range files {
+			b, err := f.read()
+			assert.NoError(t, err)
+			contents[f.Path] = string(b)
+		}
+		assert.Equal(t, map[string]string{
+			"run.js":        `import { uuidv4 } from "./_vendor/jslib.k6.io/k6-utils/1.4.0/index.js"; import "./lib/client.js";`,
+			"lib/client.js": `import { a } from "../_vendor/example.com/lib/a.js";`,
+			"_vendor/jslib.k6.io/k6-utils/1.4.0/index.js": `export function uuidv4() {}`,
+			"_vendor/example.com/lib/a.js":                `import { b } from "./b.js"; import { c } from "../c.js"; export const a = b + c;`,
+			"_vendor/example.com/lib/b.js":                `import http from "k6/http"; export const b = 1;`,
+			"_vendor/example.com/c.js":                    `export const c = 2;`,
+		}, contents)
+
+		lock, err := loadVendorLock(filepath.Join(baseDir, defaultVendorLockFile))
+		assert.NoError(t, err)
+		assert.Len(t, lock.Modules, 4)
+		assert.Equal(t, checksum([]byte(modules["https://example.com/c.js"])), lock.Modules["https://example.com/c.js"].SHA256)
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		var fetched []string
+		files, err := run(t, &fetched)
+		assert.NoError(t, err)
+		assert.Empty(t, fetched)
+		assert.Len(t, files, 6)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		assert.NoError(t, os.RemoveAll(cacheDir))
+		modules["https://example.com/c.js"] = `export const c = 3;`
+
+		var fetched []string
+		_, err := run(t, &fetched)
+		assert.ErrorContains(t, err, "checksum mismatch for https://example.com/c.js")
+	})
+
+	t.Run("fetch failure", func(t *testing.T) {
+		writeFile("run.js", `import "https://example.com/missing.js";`)
+
+		var fetched []string
+		_, err := run(t, &fetched)
+		assert.ErrorContains(t, err, "vendor module imported by run.js: fetch https://example.com/missing.js: not found")
+	})
+}